@@ -7,6 +7,8 @@ import (
 type Config struct {
 	LastInsertIDReversed bool
 	WithReturning        bool
+	// UpsertWithMerge builds creating with ON CONFLICT clause as MERGE INTO ... USING statement
+	UpsertWithMerge bool
 }
 
 func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
@@ -21,6 +23,7 @@ func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
 	createCallback.Register("gorm:create", Create(config))
 	createCallback.Register("gorm:save_after_associations", SaveAfterAssociations)
 	createCallback.Register("gorm:after_create", AfterCreate)
+	createCallback.Register("gorm:queue_transaction_hooks", QueueTransactionHooks)
 	createCallback.Match(enableTransaction).Register("gorm:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 
 	queryCallback := db.Callback().Query()
@@ -32,8 +35,9 @@ func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
 	deleteCallback.Match(enableTransaction).Register("gorm:begin_transaction", BeginTransaction)
 	deleteCallback.Register("gorm:before_delete", BeforeDelete)
 	deleteCallback.Register("gorm:delete_before_associations", DeleteBeforeAssociations)
-	deleteCallback.Register("gorm:delete", Delete)
+	deleteCallback.Register("gorm:delete", Delete(config))
 	deleteCallback.Register("gorm:after_delete", AfterDelete)
+	deleteCallback.Register("gorm:queue_transaction_hooks", QueueTransactionHooks)
 	deleteCallback.Match(enableTransaction).Register("gorm:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 
 	updateCallback := db.Callback().Update()
@@ -41,9 +45,10 @@ func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
 	updateCallback.Register("gorm:setup_reflect_value", SetupUpdateReflectValue)
 	updateCallback.Register("gorm:before_update", BeforeUpdate)
 	updateCallback.Register("gorm:save_before_associations", SaveBeforeAssociations)
-	updateCallback.Register("gorm:update", Update)
+	updateCallback.Register("gorm:update", Update(config))
 	updateCallback.Register("gorm:save_after_associations", SaveAfterAssociations)
 	updateCallback.Register("gorm:after_update", AfterUpdate)
+	updateCallback.Register("gorm:queue_transaction_hooks", QueueTransactionHooks)
 	updateCallback.Match(enableTransaction).Register("gorm:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 
 	db.Callback().Row().Register("gorm:row", RowQuery)
@@ -37,6 +37,10 @@ func Create(config *Config) func(db *gorm.DB) {
 		return func(db *gorm.DB) {
 			if db.Error == nil {
 				if db.Statement.Schema != nil && !db.Statement.Unscoped {
+					if db.Statement.Schema.TenantField != nil && db.Statement.SQL.Len() == 0 {
+						db.Statement.AddClause(gorm.TenantCreateClause{Field: db.Statement.Schema.TenantField})
+					}
+
 					for _, c := range db.Statement.Schema.CreateClauses {
 						db.Statement.AddClause(c)
 					}
@@ -45,9 +49,16 @@ func Create(config *Config) func(db *gorm.DB) {
 				if db.Statement.SQL.String() == "" {
 					db.Statement.SQL.Grow(180)
 					db.Statement.AddClauseIfNotExists(clause.Insert{})
-					db.Statement.AddClause(ConvertToCreateValues(db.Statement))
+					values := ConvertToCreateValues(db.Statement)
 
-					db.Statement.Build("INSERT", "VALUES", "ON CONFLICT")
+					if c, ok := db.Statement.Clauses["ON CONFLICT"]; ok && config.UpsertWithMerge {
+						onConflict, _ := c.Expression.(clause.OnConflict)
+						db.Statement.AddClause(ConvertToMerge(db.Statement, values, onConflict))
+						db.Statement.Build("MERGE")
+					} else {
+						db.Statement.AddClause(values)
+						db.Statement.Build("INSERT", "VALUES", "ON CONFLICT")
+					}
 				}
 
 				if !db.DryRun && db.Error == nil {
@@ -108,6 +119,10 @@ func Create(config *Config) func(db *gorm.DB) {
 func CreateWithReturning(db *gorm.DB) {
 	if db.Error == nil {
 		if db.Statement.Schema != nil && !db.Statement.Unscoped {
+			if db.Statement.Schema.TenantField != nil && db.Statement.SQL.Len() == 0 {
+				db.Statement.AddClause(gorm.TenantCreateClause{Field: db.Statement.Schema.TenantField})
+			}
+
 			for _, c := range db.Statement.Schema.CreateClauses {
 				db.Statement.AddClause(c)
 			}
@@ -329,7 +344,17 @@ func ConvertToCreateValues(stmt *gorm.Statement) (values clause.Values) {
 		}
 	}
 
-	if stmt.UpdatingColumn {
+	var (
+		onConflict clause.OnConflict
+		updateAll  = stmt.UpdatingColumn
+	)
+
+	if c, ok := stmt.Clauses["ON CONFLICT"]; ok {
+		onConflict, _ = c.Expression.(clause.OnConflict)
+		updateAll = updateAll || onConflict.UpdateAll
+	}
+
+	if updateAll {
 		if stmt.Schema != nil && len(values.Columns) > 1 {
 			columns := make([]string, 0, len(values.Columns)-1)
 			for _, column := range values.Columns {
@@ -340,13 +365,12 @@ func ConvertToCreateValues(stmt *gorm.Statement) (values clause.Values) {
 				}
 			}
 
-			onConflict := clause.OnConflict{
-				Columns:   make([]clause.Column, len(stmt.Schema.PrimaryFieldDBNames)),
-				DoUpdates: clause.AssignmentColumns(columns),
-			}
-
-			for idx, field := range stmt.Schema.PrimaryFields {
-				onConflict.Columns[idx] = clause.Column{Name: field.DBName}
+			onConflict.DoUpdates = clause.AssignmentColumns(columns)
+			if len(onConflict.Columns) == 0 {
+				onConflict.Columns = make([]clause.Column, len(stmt.Schema.PrimaryFieldDBNames))
+				for idx, field := range stmt.Schema.PrimaryFields {
+					onConflict.Columns[idx] = clause.Column{Name: field.DBName}
+				}
 			}
 			stmt.AddClause(onConflict)
 		}
@@ -354,3 +378,37 @@ func ConvertToCreateValues(stmt *gorm.Statement) (values clause.Values) {
 
 	return values
 }
+
+// ConvertToMerge convert insert values and on conflict clause to MERGE statement, for dialects don't support ON CONFLICT
+func ConvertToMerge(stmt *gorm.Statement, values clause.Values, onConflict clause.OnConflict) clause.Merge {
+	merge := clause.Merge{Values: values, OnConflict: onConflict}
+	if c, ok := stmt.Clauses["INSERT"]; ok {
+		if insert, ok := c.Expression.(clause.Insert); ok {
+			merge.Table = insert.Table
+		}
+	}
+
+	if len(merge.OnConflict.Columns) == 0 && stmt.Schema != nil {
+		merge.OnConflict.Columns = make([]clause.Column, len(stmt.Schema.PrimaryFieldDBNames))
+		for idx, dbName := range stmt.Schema.PrimaryFieldDBNames {
+			merge.OnConflict.Columns[idx] = clause.Column{Name: dbName}
+		}
+	}
+
+	// the ON condition compares the conflict columns with the inserting values
+	if len(merge.OnConflict.Columns) == 0 {
+		stmt.AddError(fmt.Errorf("%w: conflict columns required for MERGE", gorm.ErrInvalidData))
+	}
+
+	for _, column := range merge.OnConflict.Columns {
+		var found bool
+		for _, c := range values.Columns {
+			found = found || c.Name == column.Name
+		}
+
+		if !found {
+			stmt.AddError(fmt.Errorf("%w: conflict column %v isn't included in the values of MERGE", gorm.ErrInvalidData, column.Name))
+		}
+	}
+	return merge
+}
@@ -9,6 +9,9 @@ import (
 	"gorm.io/gorm/schema"
 )
 
+// deleteAssociationsBatchSize batch size of the associations loaded to be deleted with their own hooks
+const deleteAssociationsBatchSize = 100
+
 func BeforeDelete(db *gorm.DB) {
 	if db.Error == nil && db.Statement.Schema != nil && db.Statement.Schema.BeforeDelete {
 		callMethod(db, func(value interface{}, tx *gorm.DB) bool {
@@ -33,12 +36,12 @@ func DeleteBeforeAssociations(db *gorm.DB) {
 						switch rel.Type {
 						case schema.HasOne, schema.HasMany:
 							queryConds := rel.ToQueryConditions(db.Statement.ReflectValue)
-							modelValue := reflect.New(rel.FieldSchema.ModelType).Interface()
+							modelValue := reflect.New(reflect.SliceOf(rel.FieldSchema.ModelType)).Interface()
 							tx := db.Session(&gorm.Session{}).Model(modelValue)
 							withoutConditions := false
+							var selects []string
 
 							if len(db.Statement.Selects) > 0 {
-								var selects []string
 								for _, s := range db.Statement.Selects {
 									if s == clause.Associations {
 										selects = append(selects, s)
@@ -60,6 +63,29 @@ func DeleteBeforeAssociations(db *gorm.DB) {
 							}
 
 							if !withoutConditions {
+								if db.Statement.Unscoped {
+									tx = tx.Unscoped()
+								}
+								tx = tx.Session(&gorm.Session{WithConditions: true})
+
+								// load the associations in batches only if their own hooks or associations have to be processed,
+								// associations without primary keys can't be deleted one by one
+								if len(rel.FieldSchema.PrimaryFields) > 0 && (rel.FieldSchema.BeforeDelete || rel.FieldSchema.AfterDelete || len(selects) > 0) {
+									records := reflect.New(reflect.SliceOf(reflect.PtrTo(rel.FieldSchema.ModelType))).Interface()
+									query := db.Session(&gorm.Session{}).Clauses(clause.Where{Exprs: queryConds})
+									if db.Statement.Unscoped {
+										query = query.Unscoped()
+									}
+
+									if db.AddError(query.FindInKeysetBatches(records, deleteAssociationsBatchSize, func(*gorm.DB, int) error {
+										return tx.Delete(records).Error
+									}).Error) != nil {
+										return
+									}
+								}
+
+								// delete the associations by the foreign keys, includes the ones not loaded, e.g: created after loading,
+								// hooks aren't called as the value to delete is an empty slice
 								if db.AddError(tx.Clauses(clause.Where{Exprs: queryConds}).Delete(modelValue).Error) != nil {
 									return
 								}
@@ -101,52 +127,64 @@ func DeleteBeforeAssociations(db *gorm.DB) {
 	}
 }
 
-func Delete(db *gorm.DB) {
-	if db.Error == nil {
-		if db.Statement.Schema != nil && !db.Statement.Unscoped {
-			for _, c := range db.Statement.Schema.DeleteClauses {
-				db.Statement.AddClause(c)
-			}
+func Delete(config *Config) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		if _, ok := db.Statement.Clauses["RETURNING"]; ok && !config.WithReturning {
+			db.AddError(gorm.ErrUnsupportedReturning)
 		}
 
-		if db.Statement.SQL.String() == "" {
-			db.Statement.SQL.Grow(100)
-			db.Statement.AddClauseIfNotExists(clause.Delete{})
-
-			if db.Statement.Schema != nil {
-				_, queryValues := schema.GetIdentityFieldValuesMap(db.Statement.ReflectValue, db.Statement.Schema.PrimaryFields)
-				column, values := schema.ToQueryValues(db.Statement.Table, db.Statement.Schema.PrimaryFieldDBNames, queryValues)
-
-				if len(values) > 0 {
-					db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
+		if db.Error == nil {
+			if db.Statement.Schema != nil && !db.Statement.Unscoped {
+				for _, c := range db.Statement.Schema.DeleteClauses {
+					db.Statement.AddClause(c)
 				}
+			}
 
-				if db.Statement.ReflectValue.CanAddr() && db.Statement.Dest != db.Statement.Model && db.Statement.Model != nil {
-					_, queryValues = schema.GetIdentityFieldValuesMap(reflect.ValueOf(db.Statement.Model), db.Statement.Schema.PrimaryFields)
-					column, values = schema.ToQueryValues(db.Statement.Table, db.Statement.Schema.PrimaryFieldDBNames, queryValues)
+			if db.Statement.SQL.String() == "" {
+				db.Statement.SQL.Grow(100)
+				db.Statement.AddClauseIfNotExists(clause.Delete{})
+
+				if db.Statement.Schema != nil {
+					_, queryValues := schema.GetIdentityFieldValuesMap(db.Statement.ReflectValue, db.Statement.Schema.PrimaryFields)
+					column, values := schema.ToQueryValues(db.Statement.Table, db.Statement.Schema.PrimaryFieldDBNames, queryValues)
 
 					if len(values) > 0 {
 						db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
 					}
+
+					if db.Statement.ReflectValue.CanAddr() && db.Statement.Dest != db.Statement.Model && db.Statement.Model != nil {
+						_, queryValues = schema.GetIdentityFieldValuesMap(reflect.ValueOf(db.Statement.Model), db.Statement.Schema.PrimaryFields)
+						column, values = schema.ToQueryValues(db.Statement.Table, db.Statement.Schema.PrimaryFieldDBNames, queryValues)
+
+						if len(values) > 0 {
+							db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
+						}
+					}
 				}
+
+				addTenantClause(db)
+				db.Statement.AddClauseIfNotExists(clause.From{})
+				db.Statement.Build("WITH", "DELETE", "FROM", "WHERE", "RETURNING")
 			}
 
-			db.Statement.AddClauseIfNotExists(clause.From{})
-			db.Statement.Build("DELETE", "FROM", "WHERE")
-		}
+			if _, ok := db.Statement.Clauses["WHERE"]; !db.AllowGlobalUpdate && !ok && db.Error == nil {
+				db.AddError(gorm.ErrMissingWhereClause)
+				return
+			}
 
-		if _, ok := db.Statement.Clauses["WHERE"]; !db.AllowGlobalUpdate && !ok && db.Error == nil {
-			db.AddError(gorm.ErrMissingWhereClause)
-			return
-		}
+			if !db.DryRun && db.Error == nil {
+				if _, ok := db.Statement.Clauses["RETURNING"]; ok {
+					queryReturning(db)
+					return
+				}
 
-		if !db.DryRun && db.Error == nil {
-			result, err := db.Statement.ConnPool.ExecContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
+				result, err := db.Statement.ConnPool.ExecContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
 
-			if err == nil {
-				db.RowsAffected, _ = result.RowsAffected()
-			} else {
-				db.AddError(err)
+				if err == nil {
+					db.RowsAffected, _ = result.RowsAffected()
+				} else {
+					db.AddError(err)
+				}
 			}
 		}
 	}
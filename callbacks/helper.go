@@ -1,6 +1,7 @@
 package callbacks
 
 import (
+	"reflect"
 	"sort"
 
 	"gorm.io/gorm"
@@ -88,3 +89,62 @@ func ConvertSliceOfMapToValuesForCreate(stmt *gorm.Statement, mapValues []map[st
 	}
 	return
 }
+
+// queryReturning executes statement with RETURNING clause, scans returned rows into the reflect value of statement
+func queryReturning(db *gorm.DB) {
+	rows, err := db.Statement.ConnPool.QueryContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	defer rows.Close()
+
+	db.RowsAffected = 0
+	switch db.Statement.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Struct:
+		if db.Statement.ReflectValue.CanAddr() {
+			dest := db.Statement.Dest
+			db.Statement.Dest = db.Statement.ReflectValue.Addr().Interface()
+			gorm.Scan(rows, db, false)
+			db.Statement.Dest = dest
+		}
+	}
+
+	// rows not scanned into the reflect value
+	for rows.Next() {
+		db.RowsAffected++
+	}
+	db.AddError(rows.Err())
+}
+
+// addTenantClause adds tenant condition when updating or deleting, missing WHERE conditions are still reported as the tenant condition is only added to existing ones
+func addTenantClause(db *gorm.DB) {
+	if db.Statement.Schema != nil && db.Statement.Schema.TenantField != nil && !db.Statement.Unscoped {
+		if _, ok := db.Statement.Clauses["WHERE"]; ok || db.AllowGlobalUpdate {
+			db.Statement.AddClause(gorm.TenantQueryClause{Field: db.Statement.Schema.TenantField})
+		}
+	}
+}
+
+// pinTenantAssignments pins the tenant column of the update assignments to the tenant of the context, so the records
+// can't be moved to other tenants, missing tenant is reported by the tenant condition
+func pinTenantAssignments(db *gorm.DB, set clause.Set) clause.Set {
+	if db.Statement.Schema != nil && db.Statement.Schema.TenantField != nil && !db.Statement.Unscoped {
+		if tenantID, ok := gorm.TenantFromContext(db.Statement.Context); ok {
+			for idx, assignment := range set {
+				if assignment.Column.Name == db.Statement.Schema.TenantField.DBName {
+					set[idx].Value = tenantID
+				}
+			}
+		}
+	}
+	return set
+}
+
+// versionUpdateClause returns the optimistic lock clause added by the version field
+func versionUpdateClause(stmt *gorm.Statement) (vc gorm.VersionUpdateClause, ok bool) {
+	if c, exists := stmt.Clauses["version_enabled"]; exists {
+		vc, ok = c.Expression.(gorm.VersionUpdateClause)
+	}
+	return
+}
@@ -30,11 +30,29 @@ func Query(db *gorm.DB) {
 
 func BuildQuerySQL(db *gorm.DB) {
 	if db.Statement.Schema != nil && !db.Statement.Unscoped {
+		if db.Statement.Schema.TenantField != nil && db.Statement.SQL.Len() == 0 {
+			db.Statement.AddClause(gorm.TenantQueryClause{Field: db.Statement.Schema.TenantField})
+		}
+
 		for _, c := range db.Statement.Schema.QueryClauses {
 			db.Statement.AddClause(c)
 		}
 	}
 
+	if c, ok := db.Statement.Clauses["FOR"]; ok {
+		if locking, ok := c.Expression.(clause.Locking); ok {
+			if locking, err := translateLocking(db.Dialector, locking); err != nil {
+				db.AddError(err)
+				return
+			} else if locking.Strength == "" {
+				delete(db.Statement.Clauses, "FOR")
+			} else {
+				c.Expression = locking
+				db.Statement.Clauses["FOR"] = c
+			}
+		}
+	}
+
 	if db.Statement.SQL.String() == "" {
 		db.Statement.SQL.Grow(100)
 		clauseSelect := clause.Select{Distinct: db.Statement.Distinct}
@@ -160,7 +178,7 @@ func BuildQuerySQL(db *gorm.DB) {
 
 		db.Statement.AddClauseIfNotExists(clauseSelect)
 
-		db.Statement.Build("SELECT", "FROM", "WHERE", "GROUP BY", "ORDER BY", "LIMIT", "FOR")
+		db.Statement.Build("WITH", "SELECT", "FROM", "WHERE", "GROUP BY", "WINDOW", "SET OPERATIONS", "ORDER BY", "LIMIT", "FOR")
 	}
 }
 
@@ -222,3 +240,31 @@ func AfterQuery(db *gorm.DB) {
 		})
 	}
 }
+
+// translateLocking translates the locking with the dialector's LockingDialectorInterface, or the default rules of the
+// dialects lack the features, returns zero Locking to skip it
+func translateLocking(dialector gorm.Dialector, locking clause.Locking) (clause.Locking, error) {
+	if translator, ok := dialector.(gorm.LockingDialectorInterface); ok {
+		return translator.TranslateLocking(locking)
+	}
+
+	switch dialector.Name() {
+	case "mysql":
+		// the weaker lock strengths are promoted to the supported ones
+		switch locking.Strength {
+		case clause.LockingStrengthNoKeyUpdate:
+			locking.Strength = clause.LockingStrengthUpdate
+		case clause.LockingStrengthKeyShare:
+			locking.Strength = clause.LockingStrengthShare
+		}
+	case "sqlite":
+		// sqlite locks the whole database when writing, the rows can't be skipped or locked without waiting
+		if locking.Options != "" {
+			return locking, fmt.Errorf("%w: %v of sqlite", gorm.ErrUnsupportedLocking, locking.Options)
+		}
+		return clause.Locking{}, nil
+	case "sqlserver":
+		return locking, fmt.Errorf("%w: FOR %v of sqlserver, use table hints instead", gorm.ErrUnsupportedLocking, locking.Strength)
+	}
+	return locking, nil
+}
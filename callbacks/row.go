@@ -8,7 +8,7 @@ func RowQuery(db *gorm.DB) {
 	if db.Error == nil {
 		BuildQuerySQL(db)
 
-		if !db.DryRun {
+		if !db.DryRun && db.Error == nil {
 			if isRows, ok := db.InstanceGet("rows"); ok && isRows.(bool) {
 				db.Statement.Dest, db.Error = db.Statement.ConnPool.QueryContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
 			} else {
@@ -27,3 +27,29 @@ func CommitOrRollbackTransaction(db *gorm.DB) {
 		}
 	}
 }
+
+// QueueTransactionHooks queues the AfterCommit, AfterRollback methods of the values on the transaction
+func QueueTransactionHooks(db *gorm.DB) {
+	if db.Statement.Schema != nil && (db.Statement.Schema.AfterCommit || db.Statement.Schema.AfterRollback) {
+		// hooks are called after the transaction finished, so run them with the connection pool
+		tx := db.Session(&gorm.Session{Context: db.Statement.Context})
+		tx.Statement.ConnPool = db.ConnPool
+
+		callMethod(db, func(value interface{}, _ *gorm.DB) (called bool) {
+			if db.Statement.Schema.AfterCommit && db.Error == nil {
+				if i, ok := value.(AfterCommitInterface); ok {
+					called = true
+					db.OnCommit(func() { i.AfterCommit(tx) })
+				}
+			}
+
+			if db.Statement.Schema.AfterRollback {
+				if i, ok := value.(AfterRollbackInterface); ok {
+					called = true
+					db.OnRollback(func() { i.AfterRollback(tx) })
+				}
+			}
+			return called
+		})
+	}
+}
@@ -50,37 +50,59 @@ func BeforeUpdate(db *gorm.DB) {
 	}
 }
 
-func Update(db *gorm.DB) {
-	if db.Error == nil {
-		if db.Statement.Schema != nil && !db.Statement.Unscoped {
-			for _, c := range db.Statement.Schema.UpdateClauses {
-				db.Statement.AddClause(c)
-			}
+func Update(config *Config) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		if _, ok := db.Statement.Clauses["RETURNING"]; ok && !config.WithReturning {
+			db.AddError(gorm.ErrUnsupportedReturning)
 		}
 
-		if db.Statement.SQL.String() == "" {
-			db.Statement.SQL.Grow(180)
-			db.Statement.AddClauseIfNotExists(clause.Update{})
-			if set := ConvertToAssignments(db.Statement); len(set) != 0 {
-				db.Statement.AddClause(set)
-			} else {
+		if db.Error == nil {
+			if db.Statement.Schema != nil && !db.Statement.Unscoped {
+				for _, c := range db.Statement.Schema.UpdateClauses {
+					db.Statement.AddClause(c)
+				}
+			}
+
+			if db.Statement.SQL.String() == "" {
+				db.Statement.SQL.Grow(180)
+				db.Statement.AddClauseIfNotExists(clause.Update{})
+				if set := pinTenantAssignments(db, ConvertToAssignments(db.Statement)); len(set) != 0 {
+					if vc, ok := versionUpdateClause(db.Statement); ok {
+						set = append(set, vc.Assignment(db.Statement))
+					}
+					db.Statement.AddClause(set)
+				} else {
+					return
+				}
+
+				addTenantClause(db)
+				db.Statement.Build("WITH", "UPDATE", "SET", "WHERE", "RETURNING")
+			}
+
+			if _, ok := db.Statement.Clauses["WHERE"]; !db.AllowGlobalUpdate && !ok {
+				db.AddError(gorm.ErrMissingWhereClause)
 				return
 			}
-			db.Statement.Build("UPDATE", "SET", "WHERE")
-		}
 
-		if _, ok := db.Statement.Clauses["WHERE"]; !db.AllowGlobalUpdate && !ok {
-			db.AddError(gorm.ErrMissingWhereClause)
-			return
-		}
+			if !db.DryRun && db.Error == nil {
+				if _, ok := db.Statement.Clauses["RETURNING"]; ok {
+					queryReturning(db)
+					if vc, ok := versionUpdateClause(db.Statement); ok && db.Error == nil {
+						vc.Increase(db.Statement)
+					}
+					return
+				}
 
-		if !db.DryRun && db.Error == nil {
-			result, err := db.Statement.ConnPool.ExecContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
+				result, err := db.Statement.ConnPool.ExecContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
 
-			if err == nil {
-				db.RowsAffected, _ = result.RowsAffected()
-			} else {
-				db.AddError(err)
+				if err == nil {
+					db.RowsAffected, _ = result.RowsAffected()
+					if vc, ok := versionUpdateClause(db.Statement); ok {
+						vc.Increase(db.Statement)
+					}
+				} else {
+					db.AddError(err)
+				}
 			}
 		}
 	}
@@ -153,7 +175,9 @@ func ConvertToAssignments(stmt *gorm.Statement) (set clause.Set) {
 					primaryKeyExprs = append(primaryKeyExprs, clause.And(exprs...))
 				}
 			}
-			stmt.AddClause(clause.Where{Exprs: []clause.Expression{clause.Or(primaryKeyExprs...)}})
+			if len(primaryKeyExprs) > 0 {
+				stmt.AddClause(clause.Where{Exprs: []clause.Expression{clause.Or(primaryKeyExprs...)}})
+			}
 		case reflect.Struct:
 			for _, field := range stmt.Schema.PrimaryFields {
 				if value, isZero := field.ValueOf(stmt.ReflectValue); !isZero {
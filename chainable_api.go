@@ -62,6 +62,33 @@ func (db *DB) Table(name string, args ...interface{}) (tx *DB) {
 	return
 }
 
+// With specify common table expression that could be referred by name in the statement
+//    db.With("cte", db.Model(&User{}).Where("age > ?", 18)).Table("cte").Find(&users)
+//    db.With("cte", "SELECT * FROM users WHERE age > ?", 18).Table("cte").Find(&users)
+func (db *DB) With(name string, query interface{}, args ...interface{}) (tx *DB) {
+	tx = db.getInstance()
+
+	cte := clause.CTE{Name: name}
+	switch v := query.(type) {
+	case *DB:
+		cte.Subquery = clause.Expr{SQL: "?", Vars: []interface{}{v}}
+	case clause.Expression:
+		cte.Subquery = v
+	case string:
+		if strings.Contains(v, "@") && len(args) > 0 {
+			cte.Subquery = clause.NamedExpr{SQL: v, Vars: args}
+		} else {
+			cte.Subquery = clause.Expr{SQL: v, Vars: args}
+		}
+	default:
+		tx.AddError(fmt.Errorf("unsupported common table expression %v", query))
+		return
+	}
+
+	tx.Statement.AddClause(clause.With{CTEs: []clause.CTE{cte}})
+	return
+}
+
 // Distinct specify distinct fields that you want querying
 func (db *DB) Distinct(args ...interface{}) (tx *DB) {
 	tx = db.getInstance()
@@ -196,6 +223,35 @@ func (db *DB) Having(query interface{}, args ...interface{}) (tx *DB) {
 	return
 }
 
+// Union combine results of the query with another query, duplicated rows are removed
+//    db.Model(&User{}).Where("age < ?", 18).Union(db.Model(&User{}).Where("age > ?", 60)).Find(&users)
+func (db *DB) Union(query *DB) (tx *DB) {
+	tx = db.getInstance()
+	tx.Statement.AddClause(clause.Union{Query: clause.Expr{SQL: "?", Vars: []interface{}{query}}})
+	return
+}
+
+// UnionAll combine results of the query with another query, duplicated rows are kept
+func (db *DB) UnionAll(query *DB) (tx *DB) {
+	tx = db.getInstance()
+	tx.Statement.AddClause(clause.Union{All: true, Query: clause.Expr{SQL: "?", Vars: []interface{}{query}}})
+	return
+}
+
+// Intersect only keep results of the query that are also returned by another query
+func (db *DB) Intersect(query *DB) (tx *DB) {
+	tx = db.getInstance()
+	tx.Statement.AddClause(clause.Intersect{Query: clause.Expr{SQL: "?", Vars: []interface{}{query}}})
+	return
+}
+
+// Except remove results of the query that are returned by another query
+func (db *DB) Except(query *DB) (tx *DB) {
+	tx = db.getInstance()
+	tx.Statement.AddClause(clause.Except{Query: clause.Expr{SQL: "?", Vars: []interface{}{query}}})
+	return
+}
+
 // Order specify order when retrieve records from database
 //     db.Order("name DESC")
 //     db.Order(clause.OrderByColumn{Column: clause.Column{Name: "name"}, Desc: true})
@@ -284,9 +340,10 @@ func (db *DB) Raw(sql string, values ...interface{}) (tx *DB) {
 	tx.Statement.SQL = strings.Builder{}
 
 	if strings.Contains(sql, "@") {
-		clause.NamedExpr{SQL: sql, Vars: values}.Build(tx.Statement)
+		tx.Statement.raw = clause.NamedExpr{SQL: sql, Vars: values}
 	} else {
-		clause.Expr{SQL: sql, Vars: values}.Build(tx.Statement)
+		tx.Statement.raw = clause.Expr{SQL: sql, Vars: values}
 	}
+	tx.Statement.raw.Build(tx.Statement)
 	return
 }
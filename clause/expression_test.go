@@ -73,13 +73,13 @@ func TestNamedExpr(t *testing.T) {
 	}, {
 		SQL:          "@@test AND name1 = @name1 AND name2 = @name2 AND name3 = @name1 @notexist",
 		Vars:         []interface{}{sql.Named("name1", "jinzhu"), sql.Named("name2", "jinzhu2")},
-		Result:       "@@test AND name1 = ? AND name2 = ? AND name3 = ? ?",
-		ExpectedVars: []interface{}{"jinzhu", "jinzhu2", "jinzhu", nil},
+		Result:       "@@test AND name1 = ? AND name2 = ? AND name3 = ? @notexist",
+		ExpectedVars: []interface{}{"jinzhu", "jinzhu2", "jinzhu"},
 	}, {
 		SQL:          "@@test AND name1 = @Name1 AND name2 = @Name2 AND name3 = @Name1 @Notexist",
 		Vars:         []interface{}{NamedArgument{Name1: "jinzhu", Base: Base{Name2: "jinzhu2"}}},
-		Result:       "@@test AND name1 = ? AND name2 = ? AND name3 = ? ?",
-		ExpectedVars: []interface{}{"jinzhu", "jinzhu2", "jinzhu", nil},
+		Result:       "@@test AND name1 = ? AND name2 = ? AND name3 = ? @Notexist",
+		ExpectedVars: []interface{}{"jinzhu", "jinzhu2", "jinzhu"},
 	}, {
 		SQL:    "create table ? (? ?, ? ?)",
 		Vars:   []interface{}{},
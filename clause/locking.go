@@ -1,11 +1,61 @@
 package clause
 
+const (
+	LockingStrengthUpdate      = "UPDATE"
+	LockingStrengthShare       = "SHARE"
+	LockingStrengthNoKeyUpdate = "NO KEY UPDATE"
+	LockingStrengthKeyShare    = "KEY SHARE"
+	LockingOptionsSkipLocked   = "SKIP LOCKED"
+	LockingOptionsNoWait       = "NOWAIT"
+)
+
 type Locking struct {
 	Strength string
 	Table    Table
+	Tables   []Table
 	Options  string
 }
 
+// ForUpdate FOR UPDATE locking, locks the rows of tables if given
+func ForUpdate(tables ...Table) Locking {
+	return Locking{Strength: LockingStrengthUpdate, Tables: tables}
+}
+
+// ForShare FOR SHARE locking, locks the rows of tables if given
+func ForShare(tables ...Table) Locking {
+	return Locking{Strength: LockingStrengthShare, Tables: tables}
+}
+
+// ForNoKeyUpdate FOR NO KEY UPDATE locking, locks the rows of tables if given
+func ForNoKeyUpdate(tables ...Table) Locking {
+	return Locking{Strength: LockingStrengthNoKeyUpdate, Tables: tables}
+}
+
+// ForKeyShare FOR KEY SHARE locking, locks the rows of tables if given
+func ForKeyShare(tables ...Table) Locking {
+	return Locking{Strength: LockingStrengthKeyShare, Tables: tables}
+}
+
+// SkipLocked skip the rows that are locked by others
+func (locking Locking) SkipLocked() Locking {
+	locking.Options = LockingOptionsSkipLocked
+	return locking
+}
+
+// NoWait report error instead of waiting if the rows are locked by others
+func (locking Locking) NoWait() Locking {
+	locking.Options = LockingOptionsNoWait
+	return locking
+}
+
+// LockedTables returns tables of the OF list
+func (locking Locking) LockedTables() []Table {
+	if locking.Table.Name != "" {
+		return append([]Table{locking.Table}, locking.Tables...)
+	}
+	return locking.Tables
+}
+
 // Name where clause name
 func (locking Locking) Name() string {
 	return "FOR"
@@ -14,9 +64,14 @@ func (locking Locking) Name() string {
 // Build build where clause
 func (locking Locking) Build(builder Builder) {
 	builder.WriteString(locking.Strength)
-	if locking.Table.Name != "" {
+	if tables := locking.LockedTables(); len(tables) > 0 {
 		builder.WriteString(" OF ")
-		builder.WriteQuoted(locking.Table)
+		for idx, table := range tables {
+			if idx > 0 {
+				builder.WriteByte(',')
+			}
+			builder.WriteQuoted(table)
+		}
 	}
 
 	if locking.Options != "" {
@@ -25,6 +25,18 @@ func TestLocking(t *testing.T) {
 			[]clause.Interface{clause.Select{}, clause.From{}, clause.Locking{Strength: "UPDATE"}, clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}},
 			"SELECT * FROM `users` FOR UPDATE NOWAIT", nil,
 		},
+		{
+			[]clause.Interface{clause.Select{}, clause.From{}, clause.ForUpdate().SkipLocked()},
+			"SELECT * FROM `users` FOR UPDATE SKIP LOCKED", nil,
+		},
+		{
+			[]clause.Interface{clause.Select{}, clause.From{}, clause.ForNoKeyUpdate(clause.Table{Name: clause.CurrentTable}, clause.Table{Name: "companies"}).NoWait()},
+			"SELECT * FROM `users` FOR NO KEY UPDATE OF `users`,`companies` NOWAIT", nil,
+		},
+		{
+			[]clause.Interface{clause.Select{}, clause.From{}, clause.Locking{Strength: clause.LockingStrengthKeyShare, Table: clause.Table{Name: "users"}, Tables: []clause.Table{{Name: "companies"}}}},
+			"SELECT * FROM `users` FOR KEY SHARE OF `users`,`companies`", nil,
+		},
 	}
 
 	for idx, result := range results {
@@ -0,0 +1,86 @@
+package clause
+
+// Merge MERGE INTO ... USING statement, upserts values with the conflict resolution of OnConflict
+// the values are aliased as the excluded table, so assignments like AssignmentColumns work as usual,
+// OnConflict.Columns are required and have to be included in the values as they build the ON condition
+type Merge struct {
+	Table      Table
+	Values     Values
+	OnConflict OnConflict
+}
+
+// Name merge clause name
+func (merge Merge) Name() string {
+	return "MERGE"
+}
+
+// Build build merge clause
+func (merge Merge) Build(builder Builder) {
+	builder.WriteString("INTO ")
+	if merge.Table.Name == "" {
+		builder.WriteQuoted(currentTable)
+	} else {
+		builder.WriteQuoted(merge.Table)
+	}
+
+	builder.WriteString(" USING (VALUES ")
+	for idx, value := range merge.Values.Values {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+
+		builder.WriteByte('(')
+		builder.AddVar(builder, value...)
+		builder.WriteByte(')')
+	}
+	builder.WriteString(") AS ")
+	builder.WriteQuoted(Table{Name: "excluded"})
+	builder.WriteByte(' ')
+	builder.WriteQuoted(merge.Values.Columns)
+
+	target := merge.Table.Alias
+	if target == "" {
+		target = merge.Table.Name
+	}
+	if target == "" {
+		target = CurrentTable
+	}
+
+	builder.WriteString(" ON ")
+	for idx, column := range merge.OnConflict.Columns {
+		if idx > 0 {
+			builder.WriteString(" AND ")
+		}
+
+		builder.WriteQuoted(Column{Table: target, Name: column.Name})
+		builder.WriteString(" = ")
+		builder.WriteQuoted(Column{Table: "excluded", Name: column.Name})
+	}
+
+	if !merge.OnConflict.DoNothing && len(merge.OnConflict.DoUpdates) > 0 {
+		builder.WriteString(" WHEN MATCHED")
+		if exprs := append(append([]Expression{}, merge.OnConflict.Where.Exprs...), merge.OnConflict.UpdateWhere.Exprs...); len(exprs) > 0 {
+			builder.WriteString(" AND ")
+			Where{Exprs: exprs}.Build(builder)
+		}
+
+		builder.WriteString(" THEN UPDATE SET ")
+		merge.OnConflict.DoUpdates.Build(builder)
+	}
+
+	builder.WriteString(" WHEN NOT MATCHED THEN INSERT ")
+	builder.WriteQuoted(merge.Values.Columns)
+	builder.WriteString(" VALUES (")
+	for idx, column := range merge.Values.Columns {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteQuoted(Column{Table: "excluded", Name: column.Name})
+	}
+	builder.WriteString(");")
+}
+
+// MergeClause merge merge clauses
+func (merge Merge) MergeClause(clause *Clause) {
+	clause.Expression = merge
+}
@@ -1,10 +1,12 @@
 package clause
 
 type OnConflict struct {
-	Columns   []Column
-	Where     Where
-	DoNothing bool
-	DoUpdates Set
+	Columns     []Column
+	Where       Where
+	DoNothing   bool
+	DoUpdates   Set
+	UpdateAll   bool  // update all inserting columns except primary keys from the excluded row
+	UpdateWhere Where // only update the conflicting rows match the conditions, e.g: DO UPDATE SET ... WHERE
 }
 
 func (OnConflict) Name() string {
@@ -30,11 +32,17 @@ func (onConflict OnConflict) Build(builder Builder) {
 		builder.WriteByte(' ')
 	}
 
-	if onConflict.DoNothing {
+	// nothing to update, e.g: UpdateAll without any updatable columns
+	if onConflict.DoNothing || len(onConflict.DoUpdates) == 0 {
 		builder.WriteString("DO NOTHING")
 	} else {
 		builder.WriteString("DO UPDATE SET ")
 		onConflict.DoUpdates.Build(builder)
+
+		if len(onConflict.UpdateWhere.Exprs) > 0 {
+			builder.WriteString(" WHERE ")
+			onConflict.UpdateWhere.Build(builder)
+		}
 	}
 }
 
@@ -42,3 +50,42 @@ func (onConflict OnConflict) Build(builder Builder) {
 func (onConflict OnConflict) MergeClause(clause *Clause) {
 	clause.Expression = onConflict
 }
+
+// OnDuplicateKeyUpdate builds onConflict clause as ON DUPLICATE KEY UPDATE, could be registered as the "ON CONFLICT" clause builder of dialects like MySQL
+// columns of the excluded table are built as VALUES(column), conflict columns and where conditions (includes UpdateWhere) are ignored
+func OnDuplicateKeyUpdate(c Clause, builder Builder) {
+	onConflict, ok := c.Expression.(OnConflict)
+	if !ok {
+		c.Build(builder)
+		return
+	}
+
+	builder.WriteString("ON DUPLICATE KEY UPDATE ")
+	if onConflict.DoNothing || len(onConflict.DoUpdates) == 0 {
+		column := Column{Name: PrimaryKey}
+		if len(onConflict.Columns) > 0 {
+			column = onConflict.Columns[0]
+		}
+
+		builder.WriteQuoted(column)
+		builder.WriteByte('=')
+		builder.WriteQuoted(column)
+		return
+	}
+
+	for idx, assignment := range onConflict.DoUpdates {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+
+		builder.WriteQuoted(assignment.Column)
+		builder.WriteByte('=')
+		if column, ok := assignment.Value.(Column); ok && column.Table == "excluded" {
+			builder.WriteString("VALUES(")
+			builder.WriteQuoted(Column{Name: column.Name})
+			builder.WriteByte(')')
+		} else {
+			builder.AddVar(builder, assignment.Value)
+		}
+	}
+}
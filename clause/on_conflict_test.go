@@ -0,0 +1,128 @@
+package clause_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils/tests"
+)
+
+func TestOnConflict(t *testing.T) {
+	results := []struct {
+		Clauses []clause.Interface
+		Result  string
+		Vars    []interface{}
+	}{
+		{
+			[]clause.Interface{clause.Insert{}, clause.Values{Columns: []clause.Column{{Name: "name"}}, Values: [][]interface{}{{"jinzhu"}}}, clause.OnConflict{DoNothing: true}},
+			"INSERT INTO `users` (`name`) VALUES (?) ON CONFLICT DO NOTHING", []interface{}{"jinzhu"},
+		},
+		{
+			[]clause.Interface{clause.Insert{}, clause.Values{Columns: []clause.Column{{Name: "id"}, {Name: "name"}}, Values: [][]interface{}{{1, "jinzhu"}}}, clause.OnConflict{
+				Columns: []clause.Column{{Name: "id"}}, DoUpdates: clause.AssignmentColumns([]string{"name"}),
+			}},
+			"INSERT INTO `users` (`id`,`name`) VALUES (?,?) ON CONFLICT (`id`) DO UPDATE SET `name`=`excluded`.`name`", []interface{}{1, "jinzhu"},
+		},
+		{
+			[]clause.Interface{clause.Insert{}, clause.Values{Columns: []clause.Column{{Name: "id"}, {Name: "name"}}, Values: [][]interface{}{{1, "jinzhu"}}}, clause.OnConflict{
+				Columns: []clause.Column{{Name: "id"}}, DoUpdates: clause.AssignmentColumns([]string{"name"}),
+				UpdateWhere: clause.Where{Exprs: []clause.Expression{clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "tenant_id"}, Value: 1}}},
+			}},
+			"INSERT INTO `users` (`id`,`name`) VALUES (?,?) ON CONFLICT (`id`) DO UPDATE SET `name`=`excluded`.`name` WHERE `users`.`tenant_id` = ?", []interface{}{1, "jinzhu", 1},
+		},
+		{
+			[]clause.Interface{clause.Merge{
+				Values: clause.Values{Columns: []clause.Column{{Name: "id"}, {Name: "name"}}, Values: [][]interface{}{{1, "jinzhu"}, {2, "jinzhu2"}}},
+				OnConflict: clause.OnConflict{
+					Columns: []clause.Column{{Name: "id"}}, DoUpdates: clause.AssignmentColumns([]string{"name"}),
+				},
+			}},
+			"MERGE INTO `users` USING (VALUES (?,?),(?,?)) AS `excluded` (`id`,`name`) ON `users`.`id` = `excluded`.`id` WHEN MATCHED THEN UPDATE SET `name`=`excluded`.`name` WHEN NOT MATCHED THEN INSERT (`id`,`name`) VALUES (`excluded`.`id`,`excluded`.`name`);", []interface{}{1, "jinzhu", 2, "jinzhu2"},
+		},
+		{
+			[]clause.Interface{clause.Merge{
+				Values: clause.Values{Columns: []clause.Column{{Name: "id"}, {Name: "name"}}, Values: [][]interface{}{{1, "jinzhu"}}},
+				OnConflict: clause.OnConflict{
+					Columns: []clause.Column{{Name: "id"}}, DoUpdates: clause.AssignmentColumns([]string{"name"}),
+					UpdateWhere: clause.Where{Exprs: []clause.Expression{clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "tenant_id"}, Value: 1}}},
+				},
+			}},
+			"MERGE INTO `users` USING (VALUES (?,?)) AS `excluded` (`id`,`name`) ON `users`.`id` = `excluded`.`id` WHEN MATCHED AND `users`.`tenant_id` = ? THEN UPDATE SET `name`=`excluded`.`name` WHEN NOT MATCHED THEN INSERT (`id`,`name`) VALUES (`excluded`.`id`,`excluded`.`name`);", []interface{}{1, "jinzhu", 1},
+		},
+		{
+			[]clause.Interface{clause.Merge{
+				Table:      clause.Table{Name: "admins"},
+				Values:     clause.Values{Columns: []clause.Column{{Name: "name"}}, Values: [][]interface{}{{"jinzhu"}}},
+				OnConflict: clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true},
+			}},
+			"MERGE INTO `admins` USING (VALUES (?)) AS `excluded` (`name`) ON `admins`.`name` = `excluded`.`name` WHEN NOT MATCHED THEN INSERT (`name`) VALUES (`excluded`.`name`);", []interface{}{"jinzhu"},
+		},
+		{
+			[]clause.Interface{clause.Merge{
+				Table:      clause.Table{Name: "admins"},
+				Values:     clause.Values{Columns: []clause.Column{{Name: "id"}, {Name: "name"}}, Values: [][]interface{}{{1, "jinzhu"}}},
+				OnConflict: clause.OnConflict{Columns: []clause.Column{{Name: "id"}}, UpdateAll: true},
+			}},
+			"MERGE INTO `admins` USING (VALUES (?,?)) AS `excluded` (`id`,`name`) ON `admins`.`id` = `excluded`.`id` WHEN NOT MATCHED THEN INSERT (`id`,`name`) VALUES (`excluded`.`id`,`excluded`.`name`);", []interface{}{1, "jinzhu"},
+		},
+		{
+			[]clause.Interface{clause.Insert{}, clause.Values{Columns: []clause.Column{{Name: "id"}}, Values: [][]interface{}{{1}}}, clause.OnConflict{
+				Columns: []clause.Column{{Name: "id"}}, UpdateAll: true,
+			}},
+			"INSERT INTO `users` (`id`) VALUES (?) ON CONFLICT (`id`) DO NOTHING", []interface{}{1},
+		},
+	}
+
+	for idx, result := range results {
+		t.Run(fmt.Sprintf("case #%v", idx), func(t *testing.T) {
+			checkBuildClauses(t, result.Clauses, result.Result, result.Vars)
+		})
+	}
+}
+
+func TestOnDuplicateKeyUpdate(t *testing.T) {
+	results := []struct {
+		OnConflict clause.OnConflict
+		Result     string
+		Vars       []interface{}
+	}{
+		{
+			clause.OnConflict{DoNothing: true},
+			"INSERT INTO `users` (`name`) VALUES (?) ON DUPLICATE KEY UPDATE `id`=`id`", []interface{}{"jinzhu"},
+		},
+		{
+			clause.OnConflict{DoUpdates: append(clause.AssignmentColumns([]string{"name"}), clause.Assignment{Column: clause.Column{Name: "age"}, Value: 18})},
+			"INSERT INTO `users` (`name`) VALUES (?) ON DUPLICATE KEY UPDATE `name`=VALUES(`name`),`age`=?", []interface{}{"jinzhu", 18},
+		},
+	}
+
+	for idx, result := range results {
+		t.Run(fmt.Sprintf("case #%v", idx), func(t *testing.T) {
+			var (
+				db, _   = gorm.Open(tests.DummyDialector{}, nil)
+				user, _ = schema.Parse(&tests.User{}, &sync.Map{}, db.NamingStrategy)
+				stmt    = gorm.Statement{DB: db, Table: user.Table, Schema: user, Clauses: map[string]clause.Clause{}}
+			)
+
+			db.ClauseBuilders["ON CONFLICT"] = clause.OnDuplicateKeyUpdate
+			stmt.AddClause(clause.Insert{})
+			stmt.AddClause(clause.Values{Columns: []clause.Column{{Name: "name"}}, Values: [][]interface{}{{"jinzhu"}}})
+			stmt.AddClause(result.OnConflict)
+			stmt.Build("INSERT", "VALUES", "ON CONFLICT")
+
+			if strings.TrimSpace(stmt.SQL.String()) != result.Result {
+				t.Errorf("SQL expects %v got %v", result.Result, stmt.SQL.String())
+			}
+
+			if !reflect.DeepEqual(stmt.Vars, result.Vars) {
+				t.Errorf("Vars expects %+v got %v", result.Vars, stmt.Vars)
+			}
+		})
+	}
+}
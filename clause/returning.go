@@ -11,6 +11,11 @@ func (returning Returning) Name() string {
 
 // Build build where clause
 func (returning Returning) Build(builder Builder) {
+	if len(returning.Columns) == 0 {
+		builder.WriteByte('*')
+		return
+	}
+
 	for idx, column := range returning.Columns {
 		if idx > 0 {
 			builder.WriteByte(',')
@@ -0,0 +1,110 @@
+package clause
+
+// Union union clause, combines the results of the query
+type Union struct {
+	All   bool
+	Query Expression
+}
+
+// Name union clause name
+func (union Union) Name() string {
+	return "SET OPERATIONS"
+}
+
+// Build build union clause
+func (union Union) Build(builder Builder) {
+	buildSetOperation(builder, "UNION", union.All, union.Query)
+}
+
+// MergeClause merge union clause
+func (union Union) MergeClause(clause *Clause) {
+	mergeSetOperation(clause, union)
+}
+
+// Intersect intersect clause, keeps the rows also returned by the query
+type Intersect struct {
+	All   bool
+	Query Expression
+}
+
+// Name intersect clause name
+func (intersect Intersect) Name() string {
+	return "SET OPERATIONS"
+}
+
+// Build build intersect clause
+func (intersect Intersect) Build(builder Builder) {
+	buildSetOperation(builder, "INTERSECT", intersect.All, intersect.Query)
+}
+
+// MergeClause merge intersect clause
+func (intersect Intersect) MergeClause(clause *Clause) {
+	mergeSetOperation(clause, intersect)
+}
+
+// Except except clause, removes the rows returned by the query
+type Except struct {
+	All   bool
+	Query Expression
+}
+
+// Name except clause name
+func (except Except) Name() string {
+	return "SET OPERATIONS"
+}
+
+// Build build except clause
+func (except Except) Build(builder Builder) {
+	buildSetOperation(builder, "EXCEPT", except.All, except.Query)
+}
+
+// MergeClause merge except clause
+func (except Except) MergeClause(clause *Clause) {
+	mergeSetOperation(clause, except)
+}
+
+// SetOperations set operations applied in order
+type SetOperations []Expression
+
+// Build build set operations
+func (operations SetOperations) Build(builder Builder) {
+	for idx, operation := range operations {
+		if idx > 0 {
+			builder.WriteByte(' ')
+		}
+		operation.Build(builder)
+	}
+}
+
+// buildSetOperation builds the operation with the query wrapped as a derived table, so the query could have its own
+// ORDER BY and LIMIT, e.g: UNION SELECT * FROM (SELECT * FROM `admins` LIMIT 10) AS `set_operand`, as sqlite
+// doesn't support parenthesized operands
+func buildSetOperation(builder Builder, operator string, all bool, query Expression) {
+	builder.WriteString(operator)
+	if all {
+		builder.WriteString(" ALL")
+	}
+
+	if query != nil {
+		builder.WriteString(" SELECT * FROM (")
+		query.Build(builder)
+		builder.WriteString(") AS ")
+		builder.WriteQuoted("set_operand")
+	}
+}
+
+// buildSetOperations builds the set operations without the clause name, the operators are written by the operations
+func buildSetOperations(clause Clause, builder Builder) {
+	clause.Expression.Build(builder)
+}
+
+func mergeSetOperation(clause *Clause, operation Expression) {
+	var operations SetOperations
+	if v, ok := clause.Expression.(SetOperations); ok {
+		operations = make(SetOperations, len(v), len(v)+1)
+		copy(operations, v)
+	}
+
+	clause.Builder = buildSetOperations
+	clause.Expression = append(operations, operation)
+}
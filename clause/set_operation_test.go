@@ -0,0 +1,39 @@
+package clause_test
+
+import (
+	"fmt"
+	"testing"
+
+	"gorm.io/gorm/clause"
+)
+
+func TestSetOperations(t *testing.T) {
+	results := []struct {
+		Clauses []clause.Interface
+		Result  string
+		Vars    []interface{}
+	}{
+		{
+			[]clause.Interface{clause.Select{}, clause.From{}, clause.Union{
+				Query: clause.Expr{SQL: "SELECT * FROM `admins` WHERE `age` > ?", Vars: []interface{}{18}},
+			}},
+			"SELECT * FROM `users` UNION SELECT * FROM (SELECT * FROM `admins` WHERE `age` > ?) AS `set_operand`", []interface{}{18},
+		},
+		{
+			[]clause.Interface{clause.Select{}, clause.From{}, clause.Union{
+				All: true, Query: clause.Expr{SQL: "SELECT * FROM `admins`"},
+			}, clause.Except{
+				Query: clause.Expr{SQL: "SELECT * FROM `banned_users` WHERE `id` = ?", Vars: []interface{}{1}},
+			}, clause.Intersect{
+				All: true, Query: clause.Expr{SQL: "SELECT * FROM `active_users`"},
+			}, clause.OrderBy{Columns: []clause.OrderByColumn{{Column: clause.Column{Name: "name"}}}}},
+			"SELECT * FROM `users` UNION ALL SELECT * FROM (SELECT * FROM `admins`) AS `set_operand` EXCEPT SELECT * FROM (SELECT * FROM `banned_users` WHERE `id` = ?) AS `set_operand` INTERSECT ALL SELECT * FROM (SELECT * FROM `active_users`) AS `set_operand` ORDER BY `name`", []interface{}{1},
+		},
+	}
+
+	for idx, result := range results {
+		t.Run(fmt.Sprintf("case #%v", idx), func(t *testing.T) {
+			checkBuildClauses(t, result.Clauses, result.Result, result.Vars)
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package clause
+
+import "strconv"
+
+// WindowSpec window specification, refers to a named window if only Name is set
+type WindowSpec struct {
+	Name        string
+	PartitionBy []Column
+	OrderBy     []OrderByColumn
+	Frame       *WindowFrame
+}
+
+// Build build window specification
+func (spec WindowSpec) Build(builder Builder) {
+	var written bool
+	if spec.Name != "" {
+		builder.WriteQuoted(spec.Name)
+		written = true
+	}
+
+	if len(spec.PartitionBy) > 0 {
+		if written {
+			builder.WriteByte(' ')
+		}
+
+		builder.WriteString("PARTITION BY ")
+		for idx, column := range spec.PartitionBy {
+			if idx > 0 {
+				builder.WriteByte(',')
+			}
+			builder.WriteQuoted(column)
+		}
+		written = true
+	}
+
+	if len(spec.OrderBy) > 0 {
+		if written {
+			builder.WriteByte(' ')
+		}
+
+		builder.WriteString("ORDER BY ")
+		OrderBy{Columns: spec.OrderBy}.Build(builder)
+		written = true
+	}
+
+	if spec.Frame != nil {
+		if written {
+			builder.WriteByte(' ')
+		}
+		spec.Frame.Build(builder)
+	}
+}
+
+// WindowFrame window frame, e.g. ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW
+type WindowFrame struct {
+	Type  string // ROWS, RANGE or GROUPS
+	Start FrameBound
+	End   FrameBound // optional, BETWEEN Start AND End if set
+}
+
+// Build build window frame
+func (frame WindowFrame) Build(builder Builder) {
+	if frame.Type == "" {
+		builder.WriteString("ROWS ")
+	} else {
+		builder.WriteString(frame.Type)
+		builder.WriteByte(' ')
+	}
+
+	if frame.End.Type != "" {
+		builder.WriteString("BETWEEN ")
+		frame.Start.Build(builder)
+		builder.WriteString(" AND ")
+		frame.End.Build(builder)
+	} else {
+		frame.Start.Build(builder)
+	}
+}
+
+// FrameBound window frame bound
+type FrameBound struct {
+	Type   string // PRECEDING, CURRENT ROW or FOLLOWING
+	Offset int
+	// Unbounded UNBOUNDED PRECEDING or UNBOUNDED FOLLOWING, Offset is ignored
+	Unbounded bool
+}
+
+var (
+	UnboundedPreceding = FrameBound{Type: "PRECEDING", Unbounded: true}
+	UnboundedFollowing = FrameBound{Type: "FOLLOWING", Unbounded: true}
+	CurrentRow         = FrameBound{Type: "CURRENT ROW"}
+)
+
+// Preceding returns frame bound of offset rows before current row
+func Preceding(offset int) FrameBound {
+	return FrameBound{Type: "PRECEDING", Offset: offset}
+}
+
+// Following returns frame bound of offset rows after current row
+func Following(offset int) FrameBound {
+	return FrameBound{Type: "FOLLOWING", Offset: offset}
+}
+
+// Build build frame bound
+func (bound FrameBound) Build(builder Builder) {
+	if bound.Type != "CURRENT ROW" {
+		if bound.Unbounded {
+			builder.WriteString("UNBOUNDED ")
+		} else {
+			builder.WriteString(strconv.Itoa(bound.Offset))
+			builder.WriteByte(' ')
+		}
+	}
+	builder.WriteString(bound.Type)
+}
+
+// Over window function call, e.g. ROW_NUMBER() OVER (PARTITION BY `user_id` ORDER BY `amount` DESC) AS `rank`
+type Over struct {
+	Function Expression
+	Window   WindowSpec
+	Alias    string
+}
+
+// Build build window function call
+func (over Over) Build(builder Builder) {
+	if over.Function != nil {
+		over.Function.Build(builder)
+	}
+
+	builder.WriteString(" OVER ")
+	if window := over.Window; window.Name != "" && len(window.PartitionBy) == 0 && len(window.OrderBy) == 0 && window.Frame == nil {
+		builder.WriteQuoted(window.Name)
+	} else {
+		builder.WriteByte('(')
+		window.Build(builder)
+		builder.WriteByte(')')
+	}
+
+	if over.Alias != "" {
+		builder.WriteString(" AS ")
+		builder.WriteQuoted(over.Alias)
+	}
+}
+
+// RowNumber ROW_NUMBER() window function
+func RowNumber() Expression {
+	return Expr{SQL: "ROW_NUMBER()"}
+}
+
+// Rank RANK() window function
+func Rank() Expression {
+	return Expr{SQL: "RANK()"}
+}
+
+// DenseRank DENSE_RANK() window function
+func DenseRank() Expression {
+	return Expr{SQL: "DENSE_RANK()"}
+}
+
+// Lag LAG() window function, args are the optional offset and default value
+func Lag(column Column, args ...interface{}) Expression {
+	return windowFunction("LAG", column, args...)
+}
+
+// Lead LEAD() window function, args are the optional offset and default value
+func Lead(column Column, args ...interface{}) Expression {
+	return windowFunction("LEAD", column, args...)
+}
+
+// Sum SUM() aggregate function, could be used as window function with Over
+func Sum(column Column) Expression {
+	return windowFunction("SUM", column)
+}
+
+func windowFunction(name string, column Column, args ...interface{}) Expression {
+	sql := name + "(?"
+	for range args {
+		sql += ",?"
+	}
+
+	return Expr{SQL: sql + ")", Vars: append([]interface{}{column}, args...)}
+}
+
+// NamedWindow window definition of WINDOW clause
+type NamedWindow struct {
+	Name string
+	Spec WindowSpec
+}
+
+// Window window clause, defines named windows
+type Window struct {
+	Windows []NamedWindow
+}
+
+// Name window clause name
+func (window Window) Name() string {
+	return "WINDOW"
+}
+
+// Build build window clause
+func (window Window) Build(builder Builder) {
+	for idx, w := range window.Windows {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+
+		builder.WriteQuoted(w.Name)
+		builder.WriteString(" AS (")
+		w.Spec.Build(builder)
+		builder.WriteByte(')')
+	}
+}
+
+// MergeClause merge window clauses
+func (window Window) MergeClause(clause *Clause) {
+	if v, ok := clause.Expression.(Window); ok {
+		copiedWindows := make([]NamedWindow, len(v.Windows))
+		copy(copiedWindows, v.Windows)
+		window.Windows = append(copiedWindows, window.Windows...)
+	}
+
+	clause.Expression = window
+}
@@ -0,0 +1,57 @@
+package clause_test
+
+import (
+	"fmt"
+	"testing"
+
+	"gorm.io/gorm/clause"
+)
+
+func TestWindow(t *testing.T) {
+	results := []struct {
+		Clauses []clause.Interface
+		Result  string
+		Vars    []interface{}
+	}{
+		{
+			[]clause.Interface{clause.Select{Expression: clause.Expr{SQL: "*,?", Vars: []interface{}{clause.Over{
+				Function: clause.RowNumber(),
+				Window: clause.WindowSpec{
+					PartitionBy: []clause.Column{{Name: "company_id"}},
+					OrderBy:     []clause.OrderByColumn{{Column: clause.Column{Table: clause.CurrentTable, Name: "age"}, Desc: true}},
+				},
+				Alias: "rn",
+			}}}}, clause.From{}},
+			"SELECT *,ROW_NUMBER() OVER (PARTITION BY `company_id` ORDER BY `users`.`age` DESC) AS `rn` FROM `users`", nil,
+		},
+		{
+			[]clause.Interface{clause.Select{Expression: clause.Expr{SQL: "?,?", Vars: []interface{}{
+				clause.Over{
+					Function: clause.Sum(clause.Column{Name: "age"}),
+					Window: clause.WindowSpec{
+						OrderBy: []clause.OrderByColumn{{Column: clause.Column{Name: "id"}}},
+						Frame:   &clause.WindowFrame{Start: clause.UnboundedPreceding, End: clause.CurrentRow},
+					},
+				},
+				clause.Over{Function: clause.Lag(clause.Column{Name: "age"}, 1, 0), Window: clause.WindowSpec{Name: "w"}, Alias: "prev_age"},
+			}}}, clause.From{}, clause.Window{Windows: []clause.NamedWindow{{
+				Name: "w", Spec: clause.WindowSpec{OrderBy: []clause.OrderByColumn{{Column: clause.Column{Name: "id"}}}},
+			}}}, clause.Window{Windows: []clause.NamedWindow{{
+				Name: "w2", Spec: clause.WindowSpec{Name: "w", Frame: &clause.WindowFrame{Type: "RANGE", Start: clause.Preceding(2), End: clause.Following(1)}},
+			}}}},
+			"SELECT SUM(`age`) OVER (ORDER BY `id` ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW),LAG(`age`,?,?) OVER `w` AS `prev_age` FROM `users` WINDOW `w` AS (ORDER BY `id`),`w2` AS (`w` RANGE BETWEEN 2 PRECEDING AND 1 FOLLOWING)", []interface{}{1, 0},
+		},
+		{
+			[]clause.Interface{clause.Select{}, clause.From{}, clause.OrderBy{Expression: clause.Over{
+				Function: clause.DenseRank(), Window: clause.WindowSpec{OrderBy: []clause.OrderByColumn{{Column: clause.Column{Name: "age"}}}},
+			}}},
+			"SELECT * FROM `users` ORDER BY DENSE_RANK() OVER (ORDER BY `age`)", nil,
+		},
+	}
+
+	for idx, result := range results {
+		t.Run(fmt.Sprintf("case #%v", idx), func(t *testing.T) {
+			checkBuildClauses(t, result.Clauses, result.Result, result.Vars)
+		})
+	}
+}
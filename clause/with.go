@@ -1,4 +1,65 @@
 package clause
 
+// With with clause, common table expressions
 type With struct {
+	Recursive bool
+	CTEs      []CTE
+}
+
+// CTE common table expression
+type CTE struct {
+	Name     string
+	Columns  []string
+	Subquery Expression
+}
+
+// Name with clause name
+func (with With) Name() string {
+	return "WITH"
+}
+
+// Build build with clause
+func (with With) Build(builder Builder) {
+	if with.Recursive {
+		builder.WriteString("RECURSIVE ")
+	}
+
+	for idx, cte := range with.CTEs {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+		cte.Build(builder)
+	}
+}
+
+// MergeClause merge with clauses
+func (with With) MergeClause(clause *Clause) {
+	if v, ok := clause.Expression.(With); ok {
+		with.Recursive = with.Recursive || v.Recursive
+		with.CTEs = append(append([]CTE{}, v.CTEs...), with.CTEs...)
+	}
+
+	clause.Expression = with
+}
+
+// Build build common table expression
+func (cte CTE) Build(builder Builder) {
+	builder.WriteQuoted(Table{Name: cte.Name})
+
+	if len(cte.Columns) > 0 {
+		builder.WriteString(" (")
+		for idx, column := range cte.Columns {
+			if idx > 0 {
+				builder.WriteByte(',')
+			}
+			builder.WriteQuoted(Column{Name: column})
+		}
+		builder.WriteByte(')')
+	}
+
+	builder.WriteString(" AS (")
+	if cte.Subquery != nil {
+		cte.Subquery.Build(builder)
+	}
+	builder.WriteByte(')')
 }
@@ -0,0 +1,41 @@
+package clause_test
+
+import (
+	"fmt"
+	"testing"
+
+	"gorm.io/gorm/clause"
+)
+
+func TestWith(t *testing.T) {
+	results := []struct {
+		Clauses []clause.Interface
+		Result  string
+		Vars    []interface{}
+	}{
+		{
+			[]clause.Interface{clause.With{CTEs: []clause.CTE{{
+				Name:     "adults",
+				Subquery: clause.Expr{SQL: "SELECT * FROM `users` WHERE `age` >= ?", Vars: []interface{}{18}},
+			}}}, clause.Select{}, clause.From{Tables: []clause.Table{{Name: "adults"}}}},
+			"WITH `adults` AS (SELECT * FROM `users` WHERE `age` >= ?) SELECT * FROM `adults`", []interface{}{18},
+		},
+		{
+			[]clause.Interface{clause.With{CTEs: []clause.CTE{{
+				Name:     "adults",
+				Subquery: clause.Expr{SQL: "SELECT * FROM `users` WHERE `age` >= ?", Vars: []interface{}{18}},
+			}}}, clause.With{Recursive: true, CTEs: []clause.CTE{{
+				Name:     "nums",
+				Columns:  []string{"n"},
+				Subquery: clause.Expr{SQL: "SELECT 1 UNION ALL SELECT n+1 FROM `nums` WHERE n < ?", Vars: []interface{}{10}},
+			}}}, clause.Select{}, clause.From{Tables: []clause.Table{{Name: "nums"}}}},
+			"WITH RECURSIVE `adults` AS (SELECT * FROM `users` WHERE `age` >= ?),`nums` (`n`) AS (SELECT 1 UNION ALL SELECT n+1 FROM `nums` WHERE n < ?) SELECT * FROM `nums`", []interface{}{18, 10},
+		},
+	}
+
+	for idx, result := range results {
+		t.Run(fmt.Sprintf("case #%v", idx), func(t *testing.T) {
+			checkBuildClauses(t, result.Clauses, result.Result, result.Vars)
+		})
+	}
+}
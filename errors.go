@@ -2,6 +2,9 @@ package gorm
 
 import (
 	"errors"
+	"fmt"
+
+	"gorm.io/gorm/schema"
 )
 
 var (
@@ -29,6 +32,87 @@ var (
 	ErrInvalidField = errors.New("invalid field")
 	// ErrEmptySlice empty slice found
 	ErrEmptySlice = errors.New("empty slice found")
+	// ErrUnsupportedLocking unsupported row locking
+	ErrUnsupportedLocking = errors.New("unsupported locking")
+	// ErrUnsupportedReturning RETURNING clause unsupported by the dialect
+	ErrUnsupportedReturning = errors.New("unsupported returning")
+	// ErrMissingTenant tenant not found in the context
+	ErrMissingTenant = errors.New("tenant required")
+	// ErrStaleObject the record has been updated or deleted since it was loaded
+	ErrStaleObject = errors.New("stale object")
+	// ErrSubQueryRequired sub query required
+	ErrSubQueryRequired = errors.New("sub query required")
+	// ErrInvalidDB the conn pool doesn't wrap a *sql.DB
+	ErrInvalidDB = errors.New("invalid db")
 	// ErrDryRunModeUnsupported dry run mode unsupported
 	ErrDryRunModeUnsupported = errors.New("dry run mode unsupported")
+	// ErrDuplicatedKey violates unique constraint
+	ErrDuplicatedKey = errors.New("duplicated key not allowed")
+	// ErrForeignKeyViolated violates foreign key constraint
+	ErrForeignKeyViolated = errors.New("violates foreign key constraint")
+	// ErrCheckConstraintViolated violates check constraint
+	ErrCheckConstraintViolated = errors.New("violates check constraint")
+	// ErrNotNullViolated violates not null constraint
+	ErrNotNullViolated = errors.New("violates not null constraint")
+	// ErrIncompatibleDataType the data type of the column can't be changed by migration safely
+	ErrIncompatibleDataType = errors.New("incompatible data type, requires manual migration")
 )
+
+// ConstraintError constraint violation error translated from the driver error by ErrorTranslatorDialectorInterface
+//    if errors.Is(err, gorm.ErrDuplicatedKey) {
+//      var constraintErr *gorm.ConstraintError
+//      errors.As(err, &constraintErr) // constraintErr.Constraint, constraintErr.Field
+//    }
+type ConstraintError struct {
+	// Err ErrDuplicatedKey, ErrForeignKeyViolated, ErrCheckConstraintViolated or ErrNotNullViolated
+	Err error
+	// Constraint the name of the violated constraint, or the column name if the driver doesn't report the constraint
+	Constraint string
+	// Field the schema field of the constraint, set if the constraint is on one field of the statement's schema
+	Field *schema.Field
+	// Origin the driver error
+	Origin error
+}
+
+func (e *ConstraintError) Error() string {
+	if e.Origin == nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v: %v", e.Err, e.Origin)
+}
+
+func (e *ConstraintError) Is(target error) bool {
+	return target == e.Err
+}
+
+func (e *ConstraintError) Unwrap() error {
+	return e.Origin
+}
+
+// lookUpField look up the field of the constraint from the indexes, check constraints, foreign key constraints and fields
+func (e *ConstraintError) lookUpField(s *schema.Schema) {
+	if e.Field != nil || e.Constraint == "" || s == nil {
+		return
+	}
+
+	switch e.Err {
+	case ErrDuplicatedKey:
+		if idx, ok := s.ParseIndexes()[e.Constraint]; ok && len(idx.Fields) == 1 {
+			e.Field = idx.Fields[0].Field
+		}
+	case ErrCheckConstraintViolated:
+		if chk, ok := s.ParseCheckConstraints()[e.Constraint]; ok {
+			e.Field = chk.Field
+		}
+	case ErrForeignKeyViolated:
+		for _, rel := range s.Relationships.Relations {
+			if constraint := rel.ParseConstraint(); constraint != nil && constraint.Name == e.Constraint && constraint.Schema == s && len(constraint.ForeignKeys) == 1 {
+				e.Field = constraint.ForeignKeys[0]
+			}
+		}
+	}
+
+	if e.Field == nil {
+		e.Field = s.LookUpField(e.Constraint)
+	}
+}
@@ -0,0 +1,94 @@
+package gorm
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm/schema"
+)
+
+type translatorDialector struct {
+	Dialector
+}
+
+func (translatorDialector) Translate(err error) error {
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "UNIQUE constraint failed: "):
+		return &ConstraintError{Err: ErrDuplicatedKey, Constraint: strings.TrimPrefix(msg, "UNIQUE constraint failed: "), Origin: err}
+	case strings.HasPrefix(msg, "CHECK constraint failed: "):
+		return &ConstraintError{Err: ErrCheckConstraintViolated, Constraint: strings.TrimPrefix(msg, "CHECK constraint failed: "), Origin: err}
+	case strings.HasPrefix(msg, "FOREIGN KEY constraint failed: "):
+		return &ConstraintError{Err: ErrForeignKeyViolated, Constraint: strings.TrimPrefix(msg, "FOREIGN KEY constraint failed: "), Origin: err}
+	}
+	return err
+}
+
+type errorsCompany struct {
+	ID   int
+	Name string
+}
+
+type errorsUser struct {
+	ID        int
+	Name      string `gorm:"uniqueIndex:idx_name"`
+	Age       int    `gorm:"check:age_checker,age > 0"`
+	CompanyID int
+	Company   errorsCompany
+}
+
+func TestTranslateError(t *testing.T) {
+	s, err := schema.Parse(&errorsUser{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse schema, got %v", err)
+	}
+
+	tests := []struct {
+		err        error
+		expects    error
+		constraint string
+		field      string
+	}{
+		{err: errors.New("UNIQUE constraint failed: idx_name"), expects: ErrDuplicatedKey, constraint: "idx_name", field: "Name"},
+		{err: errors.New("CHECK constraint failed: age_checker"), expects: ErrCheckConstraintViolated, constraint: "age_checker", field: "Age"},
+		{err: errors.New("FOREIGN KEY constraint failed: fk_errors_users_company"), expects: ErrForeignKeyViolated, constraint: "fk_errors_users_company", field: "CompanyID"},
+		{err: errors.New("UNIQUE constraint failed: name"), expects: ErrDuplicatedKey, constraint: "name", field: "Name"},
+		{err: errors.New("UNIQUE constraint failed: unknown"), expects: ErrDuplicatedKey, constraint: "unknown"},
+	}
+
+	for _, test := range tests {
+		db := &DB{Config: &Config{Dialector: translatorDialector{}}, Statement: &Statement{Schema: s}}
+		db.AddError(test.err)
+
+		var constraintErr *ConstraintError
+		if !errors.Is(db.Error, test.expects) || !errors.As(db.Error, &constraintErr) {
+			t.Errorf("%v should be translated to %v, got %#v", test.err, test.expects, db.Error)
+			continue
+		}
+
+		if !errors.Is(db.Error, test.err) || !strings.Contains(db.Error.Error(), test.err.Error()) {
+			t.Errorf("translated error should wrap the driver error %v, got %v", test.err, db.Error)
+		}
+
+		if constraintErr.Constraint != test.constraint {
+			t.Errorf("constraint should be %v, got %v", test.constraint, constraintErr.Constraint)
+		}
+
+		if test.field == "" && constraintErr.Field != nil {
+			t.Errorf("field of %v should be nil, got %v", test.err, constraintErr.Field.Name)
+		} else if test.field != "" && (constraintErr.Field == nil || constraintErr.Field.Name != test.field) {
+			t.Errorf("field of %v should be %v, got %+v", test.err, test.field, constraintErr.Field)
+		}
+	}
+
+	db := &DB{Config: &Config{Dialector: translatorDialector{}}, Statement: &Statement{Schema: s}}
+	if db.AddError(ErrRecordNotFound); db.Error != ErrRecordNotFound {
+		t.Errorf("unknown errors should not be translated, got %v", db.Error)
+	}
+
+	if db.AddError(errors.New("UNIQUE constraint failed: idx_name")); !errors.Is(db.Error, ErrDuplicatedKey) {
+		t.Errorf("should translate the latest error, got %v", db.Error)
+	}
+}
@@ -15,12 +15,85 @@ import (
 
 // Create insert the value into database
 func (db *DB) Create(value interface{}) (tx *DB) {
+	if db.CreateBatchSize > 0 {
+		return db.CreateInBatches(value, db.CreateBatchSize)
+	}
+
 	tx = db.getInstance()
 	tx.Statement.Dest = value
 	tx.callbacks.Create().Execute(tx)
 	return
 }
 
+// CreateInBatches insert the value in batches into database, all batches share one transaction
+func (db *DB) CreateInBatches(value interface{}, batchSize int) (tx *DB) {
+	tx = db.getInstance()
+	reflectValue := reflect.Indirect(reflect.ValueOf(value))
+
+	switch reflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		if batchSize > 0 && reflectValue.Len() > batchSize && (reflectValue.Kind() == reflect.Slice || reflectValue.CanAddr()) {
+			var (
+				rowsAffected int64
+				// primary keys backfilled by the rolled back attempts, which are reset before retrying
+				primaryKeys []reflect.Value
+			)
+
+			if s, err := schema.Parse(value, db.cacheStore, db.NamingStrategy); err == nil {
+				for i := 0; i < reflectValue.Len(); i++ {
+					for _, field := range s.PrimaryFields {
+						if _, isZero := field.ValueOf(reflectValue.Index(i)); isZero {
+							primaryKeys = append(primaryKeys, field.ReflectValueOf(reflectValue.Index(i)))
+						}
+					}
+				}
+			}
+
+			resetPrimaryKeys := func() {
+				for _, primaryKey := range primaryKeys {
+					primaryKey.Set(reflect.Zero(primaryKey.Type()))
+				}
+			}
+
+			createInBatches := func(tx *DB) error {
+				rowsAffected = 0
+				for i := 0; i < reflectValue.Len(); i += batchSize {
+					ends := i + batchSize
+					if ends > reflectValue.Len() {
+						ends = reflectValue.Len()
+					}
+
+					subtx := tx.getInstance()
+					subtx.Statement.Dest = reflectValue.Slice(i, ends).Interface()
+					subtx.callbacks.Create().Execute(subtx)
+					if subtx.Error != nil {
+						return subtx.Error
+					}
+					rowsAffected += subtx.RowsAffected
+				}
+				return nil
+			}
+
+			if tx.SkipDefaultTransaction {
+				tx.AddError(createInBatches(tx.Session(&Session{WithConditions: true})))
+			} else if err := tx.Transaction(func(tx *DB) error {
+				resetPrimaryKeys()
+				return createInBatches(tx)
+			}); err != nil {
+				resetPrimaryKeys()
+				tx.AddError(err)
+			}
+
+			tx.RowsAffected = rowsAffected
+			return
+		}
+	}
+
+	tx.Statement.Dest = value
+	tx.callbacks.Create().Execute(tx)
+	return
+}
+
 // Save update value in database, if the value doesn't have primary key, will insert it
 func (db *DB) Save(value interface{}) (tx *DB) {
 	tx = db.getInstance()
@@ -138,6 +211,154 @@ func (db *DB) FindInBatches(dest interface{}, batchSize int, fc func(tx *DB, bat
 	return
 }
 
+// FindInKeysetBatches find records in batches ordered by primary keys or given columns,
+// the next batch continues after the keys of the last record instead of using offset,
+// the given columns have to be NOT NULL, and ORDER BY other than the keyset is not allowed
+//    db.Where("processed = ?", false).FindInKeysetBatches(&users, 1000, func(tx *gorm.DB, batch int) error {
+//        return nil
+//    })
+func (db *DB) FindInKeysetBatches(dest interface{}, batchSize int, fc func(tx *DB, batch int) error, columns ...string) (tx *DB) {
+	tx = db.Session(&Session{WithConditions: true})
+	rowsAffected := int64(0)
+	batch := 0
+
+	if batchSize <= 0 {
+		tx.AddError(fmt.Errorf("%w: batch size should be greater than 0", ErrInvalidData))
+		return
+	}
+
+	model := tx.Statement.Model
+	if model == nil {
+		model = dest
+	}
+
+	modelSchema, err := schema.Parse(model, db.cacheStore, db.NamingStrategy)
+	if err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	keyFields := modelSchema.PrimaryFields
+	if len(columns) > 0 {
+		keyFields = make([]*schema.Field, len(columns))
+		for idx, column := range columns {
+			if keyFields[idx] = modelSchema.LookUpField(column); keyFields[idx] == nil {
+				tx.AddError(fmt.Errorf("%w: %v", ErrInvalidField, column))
+				return
+			}
+		}
+	}
+
+	if len(keyFields) == 0 {
+		tx.AddError(ErrPrimaryKeyRequired)
+		return
+	}
+
+	// NULL keys can't be compared with >, the records after them would be skipped
+	for _, field := range keyFields {
+		if !field.PrimaryKey && !field.NotNull {
+			tx.AddError(fmt.Errorf("%w: keyset column %v should be NOT NULL", ErrInvalidField, field.DBName))
+			return
+		}
+	}
+
+	orderBy := clause.OrderBy{Columns: make([]clause.OrderByColumn, len(keyFields))}
+	for idx, field := range keyFields {
+		orderBy.Columns[idx] = clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}}
+	}
+
+	// the records are ordered by the keyset, other orders would break the pagination
+	if c, ok := tx.Statement.Clauses["ORDER BY"]; ok {
+		if existing, ok := c.Expression.(clause.OrderBy); !ok || !isKeysetOrder(existing, keyFields) {
+			tx.AddError(fmt.Errorf("%w: ORDER BY conflicts with the keyset, order by the keyset columns instead", ErrInvalidData))
+			return
+		}
+	}
+
+	var lastKeys []interface{}
+	for {
+		query := tx.Limit(batchSize)
+		delete(query.Statement.Clauses, "ORDER BY")
+		query.Statement.AddClause(orderBy)
+
+		if lastKeys != nil {
+			// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+			exprs := make([]clause.Expression, len(keyFields))
+			for idx, field := range keyFields {
+				conds := make([]clause.Expression, 0, idx+1)
+				for i := 0; i < idx; i++ {
+					conds = append(conds, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: keyFields[i].DBName}, Value: lastKeys[i]})
+				}
+				conds = append(conds, clause.Gt{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: lastKeys[idx]})
+				exprs[idx] = clause.And(conds...)
+			}
+
+			if len(exprs) == 1 {
+				query.Statement.AddClause(clause.Where{Exprs: exprs})
+			} else {
+				query.Statement.AddClause(clause.Where{Exprs: []clause.Expression{clause.Or(exprs...)}})
+			}
+		}
+
+		result := query.Find(dest)
+		rowsAffected += result.RowsAffected
+		batch++
+
+		if result.Error == nil && result.RowsAffected != 0 {
+			// read keys before the callback, which might change the records
+			if lastKeys, err = lastKeysOf(dest, keyFields); err != nil {
+				tx.AddError(err)
+				break
+			}
+
+			tx.AddError(fc(result, batch))
+		}
+
+		if tx.Error != nil || int(result.RowsAffected) < batchSize {
+			break
+		}
+	}
+
+	tx.RowsAffected = rowsAffected
+	return
+}
+
+// isKeysetOrder reports whether the order is the same as the keyset in ascending order
+func isKeysetOrder(orderBy clause.OrderBy, keyFields []*schema.Field) bool {
+	if orderBy.Expression != nil || len(orderBy.Columns) != len(keyFields) {
+		return false
+	}
+
+	for idx, column := range orderBy.Columns {
+		if column.Desc || !strings.EqualFold(strings.Trim(column.Column.Name, "`\""), keyFields[idx].DBName) {
+			return false
+		}
+	}
+	return true
+}
+
+func lastKeysOf(dest interface{}, keyFields []*schema.Field) ([]interface{}, error) {
+	reflectValue := reflect.Indirect(reflect.ValueOf(dest))
+	switch reflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		if reflectValue.Len() == 0 {
+			return nil, ErrEmptySlice
+		}
+		reflectValue = reflectValue.Index(reflectValue.Len() - 1)
+	}
+
+	reflectValue = reflect.Indirect(reflectValue)
+	if reflectValue.Kind() != reflect.Struct {
+		return nil, ErrInvalidData
+	}
+
+	keys := make([]interface{}, len(keyFields))
+	for idx, field := range keyFields {
+		keys[idx], _ = field.ValueOf(reflectValue)
+	}
+	return keys, nil
+}
+
 func (tx *DB) assignInterfacesToValue(values ...interface{}) {
 	for _, value := range values {
 		switch v := value.(type) {
@@ -292,8 +513,50 @@ func (db *DB) Delete(value interface{}, conds ...interface{}) (tx *DB) {
 	return
 }
 
+// Restore restore the soft deleted value match given conditions, if the value has primary key, then will including the primary key as condition
+func (db *DB) Restore(value interface{}, conds ...interface{}) (tx *DB) {
+	tx = db.getInstance()
+	if len(conds) > 0 {
+		tx.Statement.AddClause(clause.Where{Exprs: tx.Statement.BuildCondition(conds[0], conds[1:]...)})
+	}
+
+	if tx.Statement.Model == nil {
+		tx.Statement.Model = value
+	}
+
+	if err := tx.Statement.Parse(tx.Statement.Model); err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	values := map[string]interface{}{}
+	for _, c := range tx.Statement.Schema.DeleteClauses {
+		if sd, ok := c.(SoftDeleteDeleteClause); ok {
+			for k, v := range sd.RestoreValues() {
+				values[k] = v
+			}
+		}
+	}
+
+	if len(values) == 0 {
+		tx.AddError(fmt.Errorf("%w: %v doesn't have soft delete field", ErrInvalidField, tx.Statement.Schema))
+		return
+	}
+
+	tx.Statement.Dest = values
+	tx.Statement.UpdatingColumn = true
+	tx.callbacks.Update().Execute(tx)
+	return
+}
+
 func (db *DB) Count(count *int64) (tx *DB) {
 	tx = db.getInstance()
+
+	// counts the rows of the whole compound query, the set operations apply to the results of the query
+	if _, ok := tx.Statement.Clauses["SET OPERATIONS"]; ok {
+		return tx.Session(&Session{}).Table("(?) AS count_set_operations", tx).Count(count)
+	}
+
 	if tx.Statement.Model == nil {
 		tx.Statement.Model = tx.Statement.Dest
 		defer func() {
@@ -432,31 +695,71 @@ func (db *DB) Transaction(fc func(tx *DB) error, opts ...*sql.TxOptions) (err er
 	panicked := true
 
 	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil {
-		// nested transaction
-		db.SavePoint(fmt.Sprintf("sp%p", fc))
+		// nested transaction, creates a save point named by the depth of the nested transaction
+		depth := 1
+		if v, ok := db.Get("gorm:savepoint_depth"); ok {
+			depth = v.(int) + 1
+		}
+
+		tx := db.Session(&Session{WithConditions: true, Context: db.Statement.Context})
+		tx.Statement.Settings.Store("gorm:savepoint_depth", depth)
+
+		name := fmt.Sprintf("sp%d", depth)
+		if err = tx.SavePoint(name).Error; err != nil {
+			return
+		}
+
+		hooks := tx.transactionHooks()
+		var commits, rollbacks int
+		if hooks != nil {
+			commits, rollbacks = hooks.savePoint()
+		}
+
 		defer func() {
-			// Make sure to rollback when panic, Block error or Commit error
+			// Make sure to rollback when panic, Block error or Release error
 			if panicked || err != nil {
-				db.RollbackTo(fmt.Sprintf("sp%p", fc))
+				tx.RollbackTo(name)
+				if hooks != nil {
+					hooks.rollbackTo(commits, rollbacks)
+				}
 			}
 		}()
 
-		err = fc(db.Session(&Session{WithConditions: true}))
+		if err = fc(tx); err == nil {
+			err = tx.ReleaseSavePoint(name).Error
+		}
 	} else {
-		tx := db.Begin(opts...)
+		// retry the whole block on a new transaction if the error is retryable
+		for attempt := 1; ; attempt++ {
+			if err = db.transaction(fc, opts...); err == nil || db.RetryPolicy == nil || !db.RetryPolicy.shouldRetry(db, err, attempt) {
+				break
+			}
 
-		defer func() {
-			// Make sure to rollback when panic, Block error or Commit error
-			if panicked || err != nil {
-				tx.Rollback()
+			if err = db.RetryPolicy.wait(db.Statement.Context, attempt, err); err != nil {
+				break
 			}
-		}()
+		}
+	}
 
-		err = fc(tx)
+	panicked = false
+	return
+}
 
-		if err == nil {
-			err = tx.Commit().Error
+func (db *DB) transaction(fc func(tx *DB) error, opts ...*sql.TxOptions) (err error) {
+	panicked := true
+	tx := db.Begin(opts...)
+
+	defer func() {
+		// Make sure to rollback when panic, Block error or Commit error
+		if panicked || err != nil {
+			tx.Rollback()
 		}
+	}()
+
+	err = fc(tx)
+
+	if err == nil {
+		err = tx.Commit().Error
 	}
 
 	panicked = false
@@ -486,26 +789,31 @@ func (db *DB) Begin(opts ...*sql.TxOptions) *DB {
 
 	if err != nil {
 		tx.AddError(err)
+	} else {
+		tx.setupTransactionHooks()
 	}
 
 	return tx
 }
 
-// Commit commit a transaction
+// Commit commit a transaction, fires the hooks registered with OnCommit if succeed, otherwise the OnRollback hooks
 func (db *DB) Commit() *DB {
 	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil && !reflect.ValueOf(committer).IsNil() {
-		db.AddError(committer.Commit())
+		err := committer.Commit()
+		db.AddError(err)
+		db.fireTransactionHooks(err == nil)
 	} else {
 		db.AddError(ErrInvalidTransaction)
 	}
 	return db
 }
 
-// Rollback rollback a transaction
+// Rollback rollback a transaction, fires the hooks registered with OnRollback
 func (db *DB) Rollback() *DB {
 	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil {
 		if !reflect.ValueOf(committer).IsNil() {
 			db.AddError(committer.Rollback())
+			db.fireTransactionHooks(false)
 		}
 	} else {
 		db.AddError(ErrInvalidTransaction)
@@ -522,6 +830,17 @@ func (db *DB) SavePoint(name string) *DB {
 	return db
 }
 
+// ReleaseSavePoint release the save point with RELEASE SAVEPOINT, or the dialector's SavePointReleaserDialectorInterface,
+// does nothing for sqlserver which doesn't support it
+func (db *DB) ReleaseSavePoint(name string) *DB {
+	if releaser, ok := db.Dialector.(SavePointReleaserDialectorInterface); ok {
+		db.AddError(releaser.ReleaseSavePoint(db, name))
+	} else if db.Dialector.Name() != "sqlserver" {
+		db.AddError(db.Exec("RELEASE SAVEPOINT " + name).Error)
+	}
+	return db
+}
+
 func (db *DB) RollbackTo(name string) *DB {
 	if savePointer, ok := db.Dialector.(SavePointerDialectorInterface); ok {
 		db.AddError(savePointer.RollbackTo(db, name))
@@ -36,6 +36,10 @@ type Config struct {
 	DisableForeignKeyConstraintWhenMigrating bool
 	// AllowGlobalUpdate allow global update
 	AllowGlobalUpdate bool
+	// CreateBatchSize default create batch size
+	CreateBatchSize int
+	// RetryPolicy retries the transaction started by the Transaction method on retryable errors
+	RetryPolicy *RetryPolicy
 
 	// ClauseBuilders clause builder
 	ClauseBuilders map[string]clause.ClauseBuilder
@@ -67,6 +71,8 @@ type Session struct {
 	SkipDefaultTransaction bool
 	AllowGlobalUpdate      bool
 	FullSaveAssociations   bool
+	CreateBatchSize        int
+	RetryPolicy            *RetryPolicy
 	Context                context.Context
 	Logger                 logger.Interface
 	NowFunc                func() time.Time
@@ -169,6 +175,14 @@ func (db *DB) Session(config *Session) *DB {
 		txConfig.FullSaveAssociations = true
 	}
 
+	if config.CreateBatchSize > 0 {
+		txConfig.CreateBatchSize = config.CreateBatchSize
+	}
+
+	if config.RetryPolicy != nil {
+		txConfig.RetryPolicy = config.RetryPolicy
+	}
+
 	if config.Context != nil {
 		tx.Statement = tx.Statement.clone()
 		tx.Statement.DB = tx
@@ -252,6 +266,17 @@ func (db *DB) Callback() *callbacks {
 
 // AddError add error to db
 func (db *DB) AddError(err error) error {
+	if translator, ok := db.Dialector.(ErrorTranslatorDialectorInterface); ok && err != nil {
+		var constraintErr *ConstraintError
+		if !errors.As(err, &constraintErr) {
+			err = translator.Translate(err)
+		}
+
+		if errors.As(err, &constraintErr) && db.Statement != nil {
+			constraintErr.lookUpField(db.Statement.Schema)
+		}
+	}
+
 	if db.Error == nil {
 		db.Error = err
 	} else if err != nil {
@@ -272,7 +297,11 @@ func (db *DB) DB() (*sql.DB, error) {
 		return sqldb, nil
 	}
 
-	return nil, errors.New("invalid db")
+	if connector, ok := connPool.(GetDBConnector); ok {
+		return connector.GetDBConn()
+	}
+
+	return nil, ErrInvalidDB
 }
 
 func (db *DB) getInstance() *DB {
@@ -40,6 +40,27 @@ type SavePointerDialectorInterface interface {
 	RollbackTo(tx *DB, name string) error
 }
 
+// LockingDialectorInterface dialector that translates row locking to the supported one, which overrides the default
+// rules of mysql, sqlite and sqlserver, returns zero Locking to skip it, or error if it can't be supported
+type LockingDialectorInterface interface {
+	TranslateLocking(clause.Locking) (clause.Locking, error)
+}
+
+// SavePointReleaserDialectorInterface release save point interface
+type SavePointReleaserDialectorInterface interface {
+	ReleaseSavePoint(tx *DB, name string) error
+}
+
+// RetryableErrorDialectorInterface classifies the errors that a transaction could be retried on, e.g: serialization failure, deadlock
+type RetryableErrorDialectorInterface interface {
+	IsRetryableError(err error) bool
+}
+
+// ErrorTranslatorDialectorInterface translates the driver errors, e.g: to ConstraintError, returns the error itself if unknown
+type ErrorTranslatorDialectorInterface interface {
+	Translate(err error) error
+}
+
 type TxBeginner interface {
 	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
 }
@@ -48,6 +69,11 @@ type ConnPoolBeginner interface {
 	BeginTx(ctx context.Context, opts *sql.TxOptions) (ConnPool, error)
 }
 
+// GetDBConnector conn pool wrapping a *sql.DB, e.g: the conn pool of plugins
+type GetDBConnector interface {
+	GetDBConn() (*sql.DB, error)
+}
+
 type TxCommitter interface {
 	Commit() error
 	Rollback() error
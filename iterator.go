@@ -0,0 +1,116 @@
+package gorm
+
+import (
+	"database/sql"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// Iterator iterates the rows of a query, scans one row into the dest at a time
+type Iterator struct {
+	db      *DB
+	rows    *sql.Rows
+	scanner *fieldsScanner
+}
+
+type afterFindInterface interface {
+	AfterFind(*DB) error
+}
+
+// Iterate runs the query and returns an iterator that scans each row into dest, dest should be a pointer to struct
+//    var user User
+//    iter, err := db.Where("age > ?", 18).Iterate(&user)
+//    defer iter.Close()
+//    for iter.Next() {
+//        // user is populated with current row
+//    }
+//    err = iter.Err()
+func (db *DB) Iterate(dest interface{}) (*Iterator, error) {
+	tx := db.getInstance().InstanceSet("rows", true)
+	tx.Statement.Dest = dest
+	tx.callbacks.Row().Execute(tx)
+
+	iter := &Iterator{db: tx}
+	rows, ok := tx.Statement.Dest.(*sql.Rows)
+	if !ok {
+		if tx.DryRun && tx.Error == nil {
+			tx.Error = ErrDryRunModeUnsupported
+		}
+		return iter, tx.Error
+	}
+
+	iter.rows = rows
+	tx.Statement.Dest = dest
+	tx.RowsAffected = 0
+
+	if tx.Error == nil {
+		Schema := tx.Statement.Schema
+		if tx.Statement.ReflectValue.Kind() != reflect.Struct || Schema == nil {
+			tx.AddError(ErrInvalidData)
+		} else {
+			if tx.Statement.ReflectValue.Type() != Schema.ModelType {
+				if Schema, tx.Error = schema.Parse(dest, tx.cacheStore, tx.NamingStrategy); tx.Error != nil {
+					return iter, tx.Error
+				}
+			}
+
+			if columns, err := rows.Columns(); err != nil {
+				tx.AddError(err)
+			} else {
+				iter.scanner = newFieldsScanner(Schema, columns)
+			}
+		}
+	}
+
+	if tx.Error != nil {
+		rows.Close()
+	}
+	return iter, tx.Error
+}
+
+// Next scans the next row into dest, returns false if there are no more rows or any error happened
+func (iter *Iterator) Next() bool {
+	if iter.db.Error != nil || iter.rows == nil || !iter.rows.Next() {
+		return false
+	}
+
+	reflectValue := iter.db.Statement.ReflectValue
+	reflectValue.Set(reflect.Zero(reflectValue.Type()))
+
+	iter.db.RowsAffected++
+	if err := iter.scanner.scan(iter.rows, reflectValue); err != nil {
+		iter.db.AddError(err)
+		return false
+	}
+
+	if i, ok := reflectValue.Addr().Interface().(afterFindInterface); ok {
+		if err := i.AfterFind(iter.db.Session(&Session{})); err != nil {
+			iter.db.AddError(err)
+			return false
+		}
+	}
+
+	return true
+}
+
+// Err returns the error happened during the iteration
+func (iter *Iterator) Err() error {
+	if iter.db.Error == nil && iter.rows != nil {
+		return iter.rows.Err()
+	}
+	return iter.db.Error
+}
+
+// RowsAffected returns the number of rows scanned
+func (iter *Iterator) RowsAffected() int64 {
+	return iter.db.RowsAffected
+}
+
+// Close closes the rows, it is safe to call Close multiple times
+func (iter *Iterator) Close() error {
+	if iter.rows != nil {
+		return iter.rows.Close()
+	}
+	return nil
+}
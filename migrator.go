@@ -1,6 +1,10 @@
 package gorm
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/schema"
 )
@@ -15,13 +19,74 @@ func (db *DB) AutoMigrate(dst ...interface{}) error {
 	return db.Migrator().AutoMigrate(dst...)
 }
 
+// PlanAutoMigrate returns the DDL statements that AutoMigrate would run without executing them,
+// the queries introspecting the database are still executed
+func (db *DB) PlanAutoMigrate(dst ...interface{}) (*MigrationPlan, error) {
+	plan := &MigrationPlan{ConnPool: db.Statement.ConnPool, dialector: db.Dialector}
+	tx := db.Session(&Session{WithConditions: true, Context: db.Statement.Context})
+	tx.Statement.ConnPool = plan
+	return plan, tx.Migrator().AutoMigrate(dst...)
+}
+
+type migrationReasonKey struct{}
+
+// WithMigrationReason returns a copy of ctx with the reason of the migration statements, which is reported in MigrationPlan
+func WithMigrationReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, migrationReasonKey{}, reason)
+}
+
+// MigrationStep DDL statement of MigrationPlan
+type MigrationStep struct {
+	SQL    string
+	Reason string
+}
+
+// MigrationPlan conn pool collects the executed statements as the migration steps instead of executing them
+type MigrationPlan struct {
+	ConnPool
+	Steps     []MigrationStep
+	dialector Dialector
+}
+
+func (plan *MigrationPlan) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	reason, _ := ctx.Value(migrationReasonKey{}).(string)
+	plan.Steps = append(plan.Steps, MigrationStep{SQL: plan.dialector.Explain(query, args...), Reason: reason})
+	return driver.RowsAffected(0), nil
+}
+
+func (plan *MigrationPlan) BeginTx(ctx context.Context, opts *sql.TxOptions) (ConnPool, error) {
+	return &migrationPlanTx{plan}, nil
+}
+
+// migrationPlanTx transaction of MigrationPlan, which does nothing when committing or rollbacking as nothing executed
+type migrationPlanTx struct {
+	*MigrationPlan
+}
+
+func (*migrationPlanTx) Commit() error {
+	return nil
+}
+
+func (*migrationPlanTx) Rollback() error {
+	return nil
+}
+
 // ViewOption view option
+//    db.Migrator().CreateView("adult_users", gorm.ViewOption{Query: db.Model(&User{}).Where("age > ?", 18), CheckOption: "WITH CHECK OPTION"})
 type ViewOption struct {
-	Replace     bool
-	CheckOption string
-	Query       *DB
+	Replace     bool   // CREATE OR REPLACE VIEW
+	CheckOption string // e.g: WITH CHECK OPTION, WITH LOCAL CHECK OPTION
+	Query       *DB    // the query of the view, required
+}
+
+// ViewChecker optional interface of Migrator to check whether the view exists, it's not a part of Migrator to keep
+// the existing implementations of Migrator compatible
+//    if checker, ok := db.Migrator().(gorm.ViewChecker); ok && checker.HasView("adult_users") {}
+type ViewChecker interface {
+	HasView(name string) bool
 }
 
+// ColumnType column type interface
 type ColumnType interface {
 	Name() string
 	DatabaseTypeName() string
@@ -30,6 +95,20 @@ type ColumnType interface {
 	Nullable() (nullable bool, ok bool)
 }
 
+// ColumnTypeExtended optional interface of ColumnType with more details of the column, ok is false if the value
+// is unknown, it's not a part of ColumnType to keep the existing implementations of ColumnType compatible.
+// The drivers override Migrator.ColumnTypes have to return ColumnTypeExtended, otherwise the default value, comment
+// and unique of the columns are not migrated
+type ColumnTypeExtended interface {
+	ColumnType
+	ColumnType() (columnType string, ok bool) // varchar(64)
+	PrimaryKey() (isPrimaryKey bool, ok bool)
+	AutoIncrement() (isAutoIncrement bool, ok bool)
+	Unique() (unique bool, ok bool)
+	Comment() (value string, ok bool)
+	DefaultValue() (value string, ok bool) // NULL if the column doesn't have default value
+}
+
 type Migrator interface {
 	// AutoMigrate
 	AutoMigrate(dst ...interface{}) error
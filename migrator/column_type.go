@@ -0,0 +1,161 @@
+package migrator
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// ColumnType column type implements gorm.ColumnTypeExtended, the values read from information_schema take precedence
+// over the ones reported by the driver, ok is false if the value is unknown
+type ColumnType struct {
+	SQLColumnType      *sql.ColumnType
+	NameValue          sql.NullString
+	DataTypeValue      sql.NullString
+	ColumnTypeValue    sql.NullString
+	PrimaryKeyValue    sql.NullBool
+	UniqueValue        sql.NullBool
+	AutoIncrementValue sql.NullBool
+	LengthValue        sql.NullInt64
+	DecimalSizeValue   sql.NullInt64
+	ScaleValue         sql.NullInt64
+	NullableValue      sql.NullBool
+	CommentValue       sql.NullString
+	// DefaultValueValue the default value of the column, NULL if the column doesn't have default value
+	DefaultValueValue sql.NullString
+}
+
+// Name returns the name or alias of the column
+func (ct ColumnType) Name() string {
+	if ct.NameValue.Valid {
+		return ct.NameValue.String
+	}
+	return ct.SQLColumnType.Name()
+}
+
+// DatabaseTypeName returns the database type name of the column, e.g: varchar
+func (ct ColumnType) DatabaseTypeName() string {
+	if ct.DataTypeValue.Valid {
+		return ct.DataTypeValue.String
+	}
+	return ct.SQLColumnType.DatabaseTypeName()
+}
+
+// ColumnType returns the full data type of the column, e.g: varchar(64)
+func (ct ColumnType) ColumnType() (columnType string, ok bool) {
+	if ct.ColumnTypeValue.Valid {
+		return ct.ColumnTypeValue.String, true
+	}
+	if ct.SQLColumnType != nil {
+		return ct.SQLColumnType.DatabaseTypeName(), true
+	}
+	return "", false
+}
+
+// PrimaryKey reports whether the column is a primary key
+func (ct ColumnType) PrimaryKey() (isPrimaryKey bool, ok bool) {
+	return ct.PrimaryKeyValue.Bool, ct.PrimaryKeyValue.Valid
+}
+
+// AutoIncrement reports whether the column is auto increment
+func (ct ColumnType) AutoIncrement() (isAutoIncrement bool, ok bool) {
+	return ct.AutoIncrementValue.Bool, ct.AutoIncrementValue.Valid
+}
+
+// Length returns the length of the column for text and binary types
+func (ct ColumnType) Length() (length int64, ok bool) {
+	if ct.LengthValue.Valid {
+		return ct.LengthValue.Int64, true
+	}
+	if ct.SQLColumnType != nil {
+		return ct.SQLColumnType.Length()
+	}
+	return 0, false
+}
+
+// DecimalSize returns the precision and scale of the column for decimal types
+func (ct ColumnType) DecimalSize() (precision int64, scale int64, ok bool) {
+	if ct.DecimalSizeValue.Valid {
+		return ct.DecimalSizeValue.Int64, ct.ScaleValue.Int64, true
+	}
+	if ct.SQLColumnType != nil {
+		return ct.SQLColumnType.DecimalSize()
+	}
+	return 0, 0, false
+}
+
+// Nullable reports whether the column may be null
+func (ct ColumnType) Nullable() (nullable bool, ok bool) {
+	if ct.NullableValue.Valid {
+		return ct.NullableValue.Bool, true
+	}
+	if ct.SQLColumnType != nil {
+		return ct.SQLColumnType.Nullable()
+	}
+	return false, false
+}
+
+// Unique reports whether the column is unique
+func (ct ColumnType) Unique() (unique bool, ok bool) {
+	return ct.UniqueValue.Bool, ct.UniqueValue.Valid
+}
+
+// Comment returns the comment of the column
+func (ct ColumnType) Comment() (value string, ok bool) {
+	return ct.CommentValue.String, ct.CommentValue.Valid
+}
+
+// DefaultValue returns the default value of the column, NULL if the column doesn't have default value
+func (ct ColumnType) DefaultValue() (value string, ok bool) {
+	return ct.DefaultValueValue.String, ct.DefaultValueValue.Valid
+}
+
+// parseInformationSchema fills the column type with the row of information_schema.columns, the columns of the
+// row differ between databases, unknown columns are ignored
+func (ct *ColumnType) parseInformationSchema(columns []string, values []sql.NullString) {
+	for idx, column := range columns {
+		value := values[idx]
+		switch strings.ToLower(column) {
+		case "column_name":
+			ct.NameValue = value
+		case "data_type":
+			ct.DataTypeValue = value
+		case "column_type":
+			ct.ColumnTypeValue = value
+		case "column_default":
+			ct.DefaultValueValue = sql.NullString{String: value.String, Valid: true}
+			if !value.Valid {
+				ct.DefaultValueValue.String = "NULL"
+			}
+		case "is_nullable":
+			ct.NullableValue = sql.NullBool{Bool: strings.EqualFold(value.String, "YES"), Valid: value.Valid}
+		case "character_maximum_length":
+			ct.LengthValue = parseNullInt64(value)
+		case "numeric_precision":
+			ct.DecimalSizeValue = parseNullInt64(value)
+		case "numeric_scale":
+			ct.ScaleValue = parseNullInt64(value)
+		case "column_key":
+			ct.PrimaryKeyValue = sql.NullBool{Bool: value.String == "PRI", Valid: value.Valid}
+			ct.UniqueValue = sql.NullBool{Bool: value.String == "PRI" || value.String == "UNI", Valid: value.Valid}
+		case "extra":
+			ct.AutoIncrementValue = sql.NullBool{Bool: strings.Contains(strings.ToLower(value.String), "auto_increment"), Valid: value.Valid}
+		case "is_identity":
+			if !ct.AutoIncrementValue.Bool {
+				ct.AutoIncrementValue = sql.NullBool{Bool: strings.EqualFold(value.String, "YES"), Valid: value.Valid}
+			}
+		case "column_comment":
+			ct.CommentValue = value
+		}
+	}
+
+	// numeric_precision is reported for integer and float types as well
+	if dataType := strings.ToLower(ct.DataTypeValue.String); dataType != "decimal" && dataType != "numeric" {
+		ct.DecimalSizeValue, ct.ScaleValue = sql.NullInt64{}, sql.NullInt64{}
+	}
+}
+
+func parseNullInt64(value sql.NullString) sql.NullInt64 {
+	i, err := strconv.ParseInt(value.String, 10, 64)
+	return sql.NullInt64{Int64: i, Valid: value.Valid && err == nil}
+}
@@ -0,0 +1,144 @@
+package migrator
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DataTypeAliasesInterface dialect's aliases of data types, which are merged into the default aliases,
+// e.g: {"int4": "integer", "serial": "integer"}
+type DataTypeAliasesInterface interface {
+	DataTypeAliases() map[string]string
+}
+
+// dataTypeAliases default aliases of data types, alias -> data type
+var dataTypeAliases = map[string]string{
+	"int":                         "integer",
+	"int4":                        "integer",
+	"serial":                      "integer",
+	"serial4":                     "integer",
+	"int2":                        "smallint",
+	"smallserial":                 "smallint",
+	"serial2":                     "smallint",
+	"int8":                        "bigint",
+	"bigserial":                   "bigint",
+	"serial8":                     "bigint",
+	"float4":                      "real",
+	"float8":                      "double",
+	"double precision":            "double",
+	"bool":                        "boolean",
+	"tinyint(1)":                  "boolean",
+	"dec":                         "decimal",
+	"fixed":                       "decimal",
+	"character varying":           "varchar",
+	"character":                   "char",
+	"bpchar":                      "char",
+	"numeric":                     "decimal",
+	"timestamptz":                 "timestamp with time zone",
+	"timestamp without time zone": "timestamp",
+	"timetz":                      "time with time zone",
+	"time without time zone":      "time",
+}
+
+// dataTypeFamilies data types could be widened to the ones of the same family with higher rank
+var dataTypeFamilies = map[string]struct {
+	family string
+	rank   int
+}{
+	"tinyint":    {"integer", 1},
+	"smallint":   {"integer", 2},
+	"mediumint":  {"integer", 3},
+	"integer":    {"integer", 4},
+	"bigint":     {"integer", 5},
+	"real":       {"float", 1},
+	"float":      {"float", 1},
+	"double":     {"float", 2},
+	"char":       {"string", 1},
+	"varchar":    {"string", 2},
+	"tinytext":   {"string", 3},
+	"text":       {"string", 4},
+	"mediumtext": {"string", 5},
+	"longtext":   {"string", 6},
+}
+
+var dataTypeArgsRegexp = regexp.MustCompile(`\(.*?\)`)
+
+// normalizeDataType returns the data type without its arguments and options with the aliases resolved,
+// e.g: `int(11) unsigned NOT NULL` -> `integer`, `character varying(64)` -> `varchar`
+func normalizeDataType(dataType string, aliases map[string]string) string {
+	// aliases with arguments, e.g: tinyint(1) -> boolean
+	if fields := strings.Fields(strings.ToLower(dataType)); len(fields) > 0 && strings.Contains(fields[0], "(") {
+		if alias, ok := aliases[fields[0]]; ok {
+			return alias
+		}
+	}
+
+	words := strings.Fields(strings.ToLower(dataTypeArgsRegexp.ReplaceAllString(strings.Replace(dataType, "[]", " [] ", 1), " ")))
+	if len(words) == 0 {
+		return ""
+	}
+
+	// arrays of postgres, e.g: text[], _text (the udt_name of text[])
+	if len(words[0]) > 1 && strings.HasPrefix(words[0], "_") {
+		return normalizeDataType(words[0][1:], aliases) + "[]"
+	}
+	for idx, word := range words {
+		if word == "[]" {
+			return normalizeDataType(strings.Join(words[:idx], " "), aliases) + "[]"
+		}
+	}
+
+	for n := len(words); n > 1; n-- {
+		candidate := strings.Join(words[:n], " ")
+		if alias, ok := aliases[candidate]; ok {
+			return alias
+		}
+
+		for _, v := range aliases {
+			if v == candidate {
+				return candidate
+			}
+		}
+	}
+
+	if alias, ok := aliases[words[0]]; ok {
+		return alias
+	}
+	return words[0]
+}
+
+// isWideningDataType reports whether the data type could be safely changed from `from` to `to`
+func isWideningDataType(from, to string) bool {
+	f, ok := dataTypeFamilies[from]
+	t, ok2 := dataTypeFamilies[to]
+	return ok && ok2 && f.family == t.family && f.rank <= t.rank
+}
+
+// isKnownDataType reports whether the data type could be compared with the others, see dataTypeFamilies
+func isKnownDataType(dataType string) bool {
+	_, ok := dataTypeFamilies[dataType]
+	return ok
+}
+
+// isCompatibleDataType reports whether the data types are stored the same, e.g: MySQL stores boolean as tinyint(1),
+// which is reported as tinyint by the drivers don't return the arguments
+func isCompatibleDataType(from, to string) bool {
+	return (from == "tinyint" && to == "boolean") || (from == "boolean" && to == "tinyint")
+}
+
+// typeAliases returns the aliases of data types, includes the dialect's DataTypeAliasesInterface
+func (m Migrator) typeAliases() map[string]string {
+	aliases, ok := m.Dialector.(DataTypeAliasesInterface)
+	if !ok {
+		return dataTypeAliases
+	}
+
+	results := make(map[string]string, len(dataTypeAliases))
+	for k, v := range dataTypeAliases {
+		results[k] = v
+	}
+	for k, v := range aliases.DataTypeAliases() {
+		results[strings.ToLower(k)] = strings.ToLower(v)
+	}
+	return results
+}
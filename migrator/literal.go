@@ -0,0 +1,112 @@
+package migrator
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LiteralQuoterInterface dialect's quoter of literals, used to inline the vars of the statements which can't be
+// parameterized, e.g: the query of CREATE VIEW
+type LiteralQuoterInterface interface {
+	QuoteLiteralTo(writer clause.Writer, value interface{}) error
+}
+
+// quoteLiteralTo writes the value as a literal with the dialect's LiteralQuoterInterface or the standard SQL quoting
+func (m Migrator) quoteLiteralTo(writer clause.Writer, value interface{}) error {
+	if quoter, ok := m.Dialector.(LiteralQuoterInterface); ok {
+		return quoter.QuoteLiteralTo(writer, value)
+	}
+	return quoteLiteralTo(writer, value)
+}
+
+// quoteLiteralTo writes the value as a standard SQL literal, the values can't be quoted safely for all databases,
+// e.g: binary data, strings contain backslashes or NUL, are rejected with ErrInvalidData
+func quoteLiteralTo(writer clause.Writer, value interface{}) error {
+	if rv := reflect.ValueOf(value); !rv.IsValid() || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		writer.WriteString("NULL")
+		return nil
+	}
+
+	switch v := value.(type) {
+	case time.Time:
+		writer.WriteByte('\'')
+		writer.WriteString(v.Format("2006-01-02 15:04:05.999999999-07:00"))
+		writer.WriteByte('\'')
+		return nil
+	case driver.Valuer:
+		dv, err := v.Value()
+		if err != nil {
+			return err
+		}
+		return quoteLiteralTo(writer, dv)
+	}
+
+	switch rv := reflect.ValueOf(value); rv.Kind() {
+	case reflect.Ptr:
+		return quoteLiteralTo(writer, rv.Elem().Interface())
+	case reflect.Bool:
+		if rv.Bool() {
+			writer.WriteString("TRUE")
+		} else {
+			writer.WriteString("FALSE")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writer.WriteString(strconv.FormatInt(rv.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		writer.WriteString(strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("%w: can't quote %v as literal", gorm.ErrInvalidData, f)
+		}
+		writer.WriteString(strconv.FormatFloat(f, 'g', -1, rv.Type().Bits()))
+	case reflect.String:
+		s := rv.String()
+		if strings.ContainsAny(s, "\\\x00") {
+			return fmt.Errorf("%w: can't quote %q as literal", gorm.ErrInvalidData, s)
+		}
+		writer.WriteByte('\'')
+		writer.WriteString(strings.ReplaceAll(s, "'", "''"))
+		writer.WriteByte('\'')
+	default:
+		return fmt.Errorf("%w: can't quote %T as literal", gorm.ErrInvalidData, value)
+	}
+	return nil
+}
+
+// inlineVars replaces the bind vars of the sql with the literals of vars, the bind vars are located in order with
+// the dialect's BindVarTo
+func (m Migrator) inlineVars(sql string, vars []interface{}) (string, error) {
+	var (
+		result strings.Builder
+		probe  = &gorm.Statement{DB: m.DB}
+	)
+
+	for _, v := range vars {
+		var bindVar strings.Builder
+		probe.Vars = append(probe.Vars, v)
+		m.Dialector.BindVarTo(&bindVar, probe, v)
+
+		idx := strings.Index(sql, bindVar.String())
+		if idx < 0 {
+			return "", fmt.Errorf("%w: bind var %v not found", gorm.ErrInvalidData, bindVar.String())
+		}
+
+		result.WriteString(sql[:idx])
+		if err := m.quoteLiteralTo(&result, v); err != nil {
+			return "", err
+		}
+		sql = sql[idx+bindVar.Len():]
+	}
+
+	result.WriteString(sql)
+	return result.String(), nil
+}
@@ -2,13 +2,16 @@ package migrator
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
 )
 
@@ -84,7 +87,7 @@ func (m Migrator) AutoMigrate(values ...interface{}) error {
 	for _, value := range m.ReorderModels(values, true) {
 		tx := m.DB.Session(&gorm.Session{})
 		if !tx.Migrator().HasTable(value) {
-			if err := tx.Migrator().CreateTable(value); err != nil {
+			if err := withReason(tx, "table not found").Migrator().CreateTable(value); err != nil {
 				return err
 			}
 		} else {
@@ -103,7 +106,7 @@ func (m Migrator) AutoMigrate(values ...interface{}) error {
 
 					if foundColumn == nil {
 						// not found, add column
-						if err := tx.Migrator().AddColumn(value, field.DBName); err != nil {
+						if err := withReason(tx, "column %v not found", field.DBName).Migrator().AddColumn(value, field.DBName); err != nil {
 							return err
 						}
 					} else if err := m.DB.Migrator().MigrateColumn(value, field, foundColumn); err != nil {
@@ -117,7 +120,7 @@ func (m Migrator) AutoMigrate(values ...interface{}) error {
 						if constraint := rel.ParseConstraint(); constraint != nil {
 							if constraint.Schema == stmt.Schema {
 								if !tx.Migrator().HasConstraint(value, constraint.Name) {
-									if err := tx.Migrator().CreateConstraint(value, constraint.Name); err != nil {
+									if err := withReason(tx, "constraint %v not found", constraint.Name).Migrator().CreateConstraint(value, constraint.Name); err != nil {
 										return err
 									}
 								}
@@ -127,7 +130,7 @@ func (m Migrator) AutoMigrate(values ...interface{}) error {
 
 					for _, chk := range stmt.Schema.ParseCheckConstraints() {
 						if !tx.Migrator().HasConstraint(value, chk.Name) {
-							if err := tx.Migrator().CreateConstraint(value, chk.Name); err != nil {
+							if err := withReason(tx, "constraint %v not found", chk.Name).Migrator().CreateConstraint(value, chk.Name); err != nil {
 								return err
 							}
 						}
@@ -136,7 +139,7 @@ func (m Migrator) AutoMigrate(values ...interface{}) error {
 
 				for _, idx := range stmt.Schema.ParseIndexes() {
 					if !tx.Migrator().HasIndex(value, idx.Name) {
-						if err := tx.Migrator().CreateIndex(value, idx.Name); err != nil {
+						if err := withReason(tx, "index %v not found", idx.Name).Migrator().CreateIndex(value, idx.Name); err != nil {
 							return err
 						}
 					}
@@ -156,6 +159,11 @@ func (m Migrator) CreateTable(values ...interface{}) error {
 	for _, value := range m.ReorderModels(values, false) {
 		tx := m.DB.Session(&gorm.Session{})
 		if err := m.RunWithValue(value, func(stmt *gorm.Statement) (errr error) {
+			// models mapped onto views are created by CreateView
+			if stmt.Schema.IsView {
+				return nil
+			}
+
 			var (
 				createTableSQL          = "CREATE TABLE ? ("
 				values                  = []interface{}{m.CurrentTable(stmt)}
@@ -363,18 +371,43 @@ func (m Migrator) MigrateColumn(value interface{}, field *schema.Field, columnTy
 	fullDataType := strings.ToLower(m.DB.Migrator().FullDataTypeOf(field).SQL)
 	realDataType := strings.ToLower(columnType.DatabaseTypeName())
 
-	alterColumn := false
+	var (
+		reasons      []string
+		incompatible bool
+	)
+
+	// check data type, only widening changes will be applied, e.g: smallint -> bigint, varchar -> text
+	aliases, dataType := m.typeAliases(), realDataType
+	if extended, ok := columnType.(gorm.ColumnTypeExtended); ok {
+		if ct, ok := extended.ColumnType(); ok {
+			dataType = ct
+		}
+	}
+
+	if from, to := normalizeDataType(dataType, aliases), normalizeDataType(fullDataType, aliases); from != to && !isCompatibleDataType(from, to) {
+		switch {
+		case isWideningDataType(from, to):
+			reasons = append(reasons, fmt.Sprintf("column %v type %v != %v", field.DBName, from, to))
+		case isKnownDataType(from) && isKnownDataType(to):
+			incompatible = true
+			reasons = append(reasons, fmt.Sprintf("column %v type %v can't be changed to %v", field.DBName, from, to))
+		default:
+			// unknown data types can't be compared, AlterColumn might change the data type unexpectedly
+			m.DB.Logger.Warn(m.DB.Statement.Context, "column %v can't be migrated from %v to %v, requires manual migration", field.DBName, dataType, fullDataType)
+			return nil
+		}
+	}
 
 	// check size
 	if length, _ := columnType.Length(); length != int64(field.Size) {
 		if length > 0 && field.Size > 0 {
-			alterColumn = true
+			reasons = append(reasons, fmt.Sprintf("column %v size %v != %v", field.DBName, length, field.Size))
 		} else {
 			// has size in data type and not equal
 			matches := regexp.MustCompile(`[^\d](\d+)[^\d]?`).FindAllStringSubmatch(realDataType, -1)
 			matches2 := regexp.MustCompile(`[^\d]*(\d+)[^\d]?`).FindAllStringSubmatch(fullDataType, -1)
 			if (len(matches) == 1 && matches[0][1] != fmt.Sprint(field.Size) || !field.PrimaryKey) && (len(matches2) == 1 && matches2[0][1] != fmt.Sprint(length)) {
-				alterColumn = true
+				reasons = append(reasons, fmt.Sprintf("column %v type %v != %v", field.DBName, realDataType, fullDataType))
 			}
 		}
 	}
@@ -382,7 +415,7 @@ func (m Migrator) MigrateColumn(value interface{}, field *schema.Field, columnTy
 	// check precision
 	if precision, _, ok := columnType.DecimalSize(); ok && int64(field.Precision) != precision {
 		if strings.Contains(fullDataType, fmt.Sprint(field.Precision)) {
-			alterColumn = true
+			reasons = append(reasons, fmt.Sprintf("column %v precision %v != %v", field.DBName, precision, field.Precision))
 		}
 	}
 
@@ -390,12 +423,34 @@ func (m Migrator) MigrateColumn(value interface{}, field *schema.Field, columnTy
 	if nullable, ok := columnType.Nullable(); ok && nullable == field.NotNull {
 		// not primary key & database is nullable
 		if !field.PrimaryKey && nullable {
-			alterColumn = true
+			reasons = append(reasons, fmt.Sprintf("column %v should be not null", field.DBName))
+		}
+	}
+
+	if extended, ok := columnType.(gorm.ColumnTypeExtended); ok && m.alterColumnOptions() {
+		// check default value
+		if dv, ok := extended.DefaultValue(); ok && !field.PrimaryKey && !sameDefaultValue(field, dv) {
+			reasons = append(reasons, fmt.Sprintf("column %v default %v != %v", field.DBName, dv, field.DefaultValue))
+		}
+
+		// check comment
+		if comment, ok := extended.Comment(); ok && comment != field.Comment {
+			reasons = append(reasons, fmt.Sprintf("column %v comment %q != %q", field.DBName, comment, field.Comment))
+		}
+
+		// check unique, removing unique requires dropping the index or constraint which won't be done by AlterColumn
+		if unique, ok := extended.Unique(); ok && !unique && field.Unique {
+			reasons = append(reasons, fmt.Sprintf("column %v should be unique", field.DBName))
 		}
 	}
 
-	if alterColumn {
-		return m.DB.Migrator().AlterColumn(value, field.Name)
+	// AlterColumn changes the data type as well, the column has to be migrated manually
+	if incompatible {
+		return fmt.Errorf("%w: %v", gorm.ErrIncompatibleDataType, strings.Join(reasons, "; "))
+	}
+
+	if len(reasons) > 0 {
+		return withReason(m.DB, strings.Join(reasons, "; ")).Migrator().AlterColumn(value, field.Name)
 	}
 
 	return nil
@@ -405,26 +460,129 @@ func (m Migrator) ColumnTypes(value interface{}) (columnTypes []gorm.ColumnType,
 	columnTypes = make([]gorm.ColumnType, 0)
 	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
 		rows, err := m.DB.Session(&gorm.Session{}).Table(stmt.Table).Limit(1).Rows()
-		if err == nil {
-			defer rows.Close()
-			rawColumnTypes, err := rows.ColumnTypes()
-			if err == nil {
-				for _, c := range rawColumnTypes {
-					columnTypes = append(columnTypes, c)
-				}
-			}
+		if err != nil {
+			return err
 		}
-		return err
+
+		rawColumnTypes, err := rows.ColumnTypes()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+
+		informationSchema := m.informationSchemaColumns(stmt)
+		for _, c := range rawColumnTypes {
+			columnType := informationSchema[c.Name()]
+			columnType.SQLColumnType = c
+			columnTypes = append(columnTypes, columnType)
+		}
+		return nil
 	})
 	return
 }
 
+// informationSchemaColumns reads the columns of the table from information_schema, returns nil if it is unavailable
+func (m Migrator) informationSchemaColumns(stmt *gorm.Statement) map[string]ColumnType {
+	rows, err := m.DB.Session(&gorm.Session{Logger: logger.Discard}).Raw(
+		"SELECT * FROM information_schema.columns WHERE table_schema = ? AND table_name = ?", m.currentSchema(), stmt.Table,
+	).Rows()
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil
+	}
+
+	columnTypes := map[string]ColumnType{}
+	values := make([]sql.NullString, len(columns))
+	dests := make([]interface{}, len(columns))
+	for idx := range values {
+		dests[idx] = &values[idx]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(dests...); err != nil {
+			return nil
+		}
+
+		var columnType ColumnType
+		columnType.parseInformationSchema(columns, values)
+		columnTypes[columnType.NameValue.String] = columnType
+	}
+	return columnTypes
+}
+
+// CreateView creates view name from the query, the vars of the query are inlined as literals as views can't be
+// parameterized, see LiteralQuoterInterface
 func (m Migrator) CreateView(name string, option gorm.ViewOption) error {
-	return gorm.ErrNotImplemented
+	if option.Query == nil {
+		return gorm.ErrSubQueryRequired
+	}
+
+	tx := m.DB.Session(&gorm.Session{})
+	stmt := &gorm.Statement{DB: tx}
+	stmt.WriteString("CREATE ")
+	if option.Replace {
+		stmt.WriteString("OR REPLACE ")
+	}
+	stmt.WriteString("VIEW ")
+	stmt.WriteQuoted(name)
+	stmt.WriteString(" AS ")
+	stmt.AddVar(stmt, option.Query)
+
+	if option.CheckOption != "" {
+		stmt.WriteString(" ")
+		stmt.WriteString(option.CheckOption)
+	}
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	sql, err := m.inlineVars(stmt.SQL.String(), stmt.Vars)
+	if err != nil {
+		return err
+	}
+	return m.DB.Exec("?", clause.Expr{SQL: sql}).Error
 }
 
 func (m Migrator) DropView(name string) error {
-	return gorm.ErrNotImplemented
+	return m.DB.Exec("DROP VIEW IF EXISTS ?", clause.Table{Name: name}).Error
+}
+
+// HasView reports whether the view exists in the current schema, implements gorm.ViewChecker
+func (m Migrator) HasView(name string) bool {
+	var count int64
+	switch m.Dialector.Name() {
+	case "sqlite":
+		m.DB.Raw("SELECT count(*) FROM sqlite_master WHERE type = ? AND name = ?", "view", name).Row().Scan(&count)
+	default:
+		m.DB.Raw("SELECT count(*) FROM information_schema.views WHERE table_schema = ? AND table_name = ?", m.currentSchema(), name).Row().Scan(&count)
+	}
+	return count > 0
+}
+
+// CurrentSchemaInterface dialect's current schema of information_schema, e.g: the search path of postgres
+type CurrentSchemaInterface interface {
+	CurrentSchema() clause.Expr
+}
+
+// currentSchema returns the expression of the current schema, which filters the tables of information_schema
+func (m Migrator) currentSchema() clause.Expr {
+	if current, ok := m.Dialector.(CurrentSchemaInterface); ok {
+		return current.CurrentSchema()
+	}
+
+	switch m.Dialector.Name() {
+	case "postgres":
+		return clause.Expr{SQL: "CURRENT_SCHEMA()"}
+	case "sqlserver":
+		return clause.Expr{SQL: "SCHEMA_NAME()"}
+	}
+	return clause.Expr{SQL: "?", Vars: []interface{}{m.DB.Migrator().CurrentDatabase()}}
 }
 
 func buildConstraint(constraint *schema.Constraint) (sql string, results []interface{}) {
@@ -704,3 +862,62 @@ func (m Migrator) CurrentTable(stmt *gorm.Statement) interface{} {
 	}
 	return clause.Table{Name: stmt.Table}
 }
+
+// sameDefaultValue reports whether the default value of the database is the same as the field's
+// AlterColumnOptionsInterface dialect's AlterColumn applies the default value, comment and unique of the field besides
+// its data type, MigrateColumn only checks them for the dialects could apply them
+type AlterColumnOptionsInterface interface {
+	AlterColumnOptions() bool
+}
+
+// alterColumnOptions reports whether AlterColumn of the dialect applies the default value, comment and unique, the
+// column is redefined by MODIFY COLUMN of mysql and the recreated table of sqlite
+func (m Migrator) alterColumnOptions() bool {
+	if options, ok := m.Dialector.(AlterColumnOptionsInterface); ok {
+		return options.AlterColumnOptions()
+	}
+
+	switch m.Dialector.Name() {
+	case "mysql", "sqlite":
+		return true
+	}
+	return false
+}
+
+func sameDefaultValue(field *schema.Field, dv string) bool {
+	defaultValue := "NULL"
+	if field.HasDefaultValue && field.DefaultValueInterface != nil {
+		defaultValue = fmt.Sprint(field.DefaultValueInterface)
+	} else if field.HasDefaultValue && field.DefaultValue != "" && field.DefaultValue != "(-)" {
+		defaultValue = field.DefaultValue
+	}
+
+	// e.g: ((0)), ('jinzhu') of sqlserver
+	for len(dv) > 1 && dv[0] == '(' && dv[len(dv)-1] == ')' {
+		dv = dv[1 : len(dv)-1]
+	}
+
+	// e.g: 'jinzhu'::character varying, CURRENT_TIMESTAMP()
+	dv = strings.TrimSuffix(strings.Trim(strings.SplitN(dv, "::", 2)[0], "'\""), "()")
+	defaultValue = strings.TrimSuffix(strings.Trim(defaultValue, "'\""), "()")
+	if strings.EqualFold(dv, defaultValue) {
+		return true
+	}
+
+	switch field.DataType {
+	case schema.Bool:
+		b1, err1 := strconv.ParseBool(dv)
+		b2, err2 := strconv.ParseBool(defaultValue)
+		return err1 == nil && err2 == nil && b1 == b2
+	case schema.Int, schema.Uint, schema.Float:
+		f1, err1 := strconv.ParseFloat(dv, 64)
+		f2, err2 := strconv.ParseFloat(defaultValue, 64)
+		return err1 == nil && err2 == nil && f1 == f2
+	}
+	return false
+}
+
+// withReason returns a session with the reason of the migration statements, see gorm.MigrationPlan
+func withReason(db *gorm.DB, format string, args ...interface{}) *gorm.DB {
+	return db.Session(&gorm.Session{Context: gorm.WithMigrationReason(db.Statement.Context, fmt.Sprintf(format, args...))})
+}
@@ -0,0 +1,287 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	// ErrMigrationLocked failed to acquire the migration lock before timeout
+	ErrMigrationLocked = errors.New("migration locked")
+	// ErrUnknownMigration migration not found
+	ErrUnknownMigration = errors.New("unknown migration")
+	// ErrIrreversibleMigration migration doesn't have Rollback
+	ErrIrreversibleMigration = errors.New("irreversible migration")
+	// ErrInvalidMigrationID migration id is empty or duplicated
+	ErrInvalidMigrationID = errors.New("invalid migration id")
+)
+
+const (
+	// DefaultMigrationTableName default history table name of VersionedMigrator
+	DefaultMigrationTableName = "schema_migrations"
+	// DefaultStaleLockTimeout default duration after that the lock of VersionedMigrator is considered stale
+	DefaultStaleLockTimeout = time.Hour
+)
+
+// Migration versioned migration, migrations are applied in the order of their IDs
+type Migration struct {
+	// ID unique and ordered id, e.g: 202010161200_create_users
+	ID       string
+	Migrate  func(tx *gorm.DB) error
+	Rollback func(tx *gorm.DB) error
+}
+
+// MigrationRecord applied migration of the history table
+type MigrationRecord struct {
+	ID        string `gorm:"primaryKey;size:255"`
+	AppliedAt time.Time
+}
+
+// MigrationLock lock record of the lock table, only one record could exist
+type MigrationLock struct {
+	ID       int `gorm:"primaryKey;autoIncrement:false"`
+	LockedAt time.Time
+}
+
+// TransactionalDDLInterface reports whether the DDL statements could be rolled back in a transaction
+type TransactionalDDLInterface interface {
+	SupportsTransactionalDDL() bool
+}
+
+// VersionedMigrator runs versioned migrations and records the applied ones in the history table,
+// a lock table prevents that the migrations are run by multiple instances at the same time
+//    m := migrator.VersionedMigrator{DB: db, Migrations: []*migrator.Migration{{
+//      ID:       "202010161200_create_users",
+//      Migrate:  func(tx *gorm.DB) error { return tx.Migrator().CreateTable(&User{}) },
+//      Rollback: func(tx *gorm.DB) error { return tx.Migrator().DropTable(&User{}) },
+//    }}}
+//    m.Migrate()
+type VersionedMigrator struct {
+	DB         *gorm.DB
+	Migrations []*Migration
+	// TableName history table name, defaults to DefaultMigrationTableName
+	TableName string
+	// LockTimeout max duration to wait for the lock, defaults to 1 minute
+	LockTimeout time.Duration
+	// StaleLockTimeout the lock held longer than it is considered left by a crashed instance and released,
+	// defaults to DefaultStaleLockTimeout, should be longer than the migrations take
+	StaleLockTimeout time.Duration
+	// DisableTransaction don't run migrations in transactions even the dialect supports transactional DDL
+	DisableTransaction bool
+}
+
+// Migrate applies all pending migrations
+func (m VersionedMigrator) Migrate() error {
+	return m.MigrateTo("")
+}
+
+// MigrateTo applies the pending migrations up to id, and rollbacks the applied migrations after it
+func (m VersionedMigrator) MigrateTo(id string) error {
+	migrations, err := m.sortedMigrations()
+	if err != nil {
+		return err
+	}
+
+	target := len(migrations) - 1
+	if id != "" {
+		if target = sort.Search(len(migrations), func(i int) bool { return migrations[i].ID >= id }); target == len(migrations) || migrations[target].ID != id {
+			return fmt.Errorf("%w: %v", ErrUnknownMigration, id)
+		}
+	}
+
+	return m.withLock(func() error {
+		applied, err := m.Applied()
+		if err != nil {
+			return err
+		}
+
+		appliedMap := map[string]bool{}
+		for _, id := range applied {
+			appliedMap[id] = true
+		}
+
+		for _, migration := range migrations[:target+1] {
+			if !appliedMap[migration.ID] {
+				if err := m.migrate(migration); err != nil {
+					return err
+				}
+			}
+		}
+
+		for i := len(migrations) - 1; i > target; i-- {
+			if appliedMap[migrations[i].ID] {
+				if err := m.rollback(migrations[i]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Rollback rollbacks the last n applied migrations
+func (m VersionedMigrator) Rollback(n int) error {
+	migrations, err := m.sortedMigrations()
+	if err != nil {
+		return err
+	}
+
+	migrationsMap := map[string]*Migration{}
+	for _, migration := range migrations {
+		migrationsMap[migration.ID] = migration
+	}
+
+	return m.withLock(func() error {
+		applied, err := m.Applied()
+		if err != nil {
+			return err
+		}
+
+		for i := len(applied) - 1; i >= 0 && i >= len(applied)-n; i-- {
+			migration, ok := migrationsMap[applied[i]]
+			if !ok {
+				return fmt.Errorf("%w: %v", ErrUnknownMigration, applied[i])
+			}
+
+			if err := m.rollback(migration); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Applied returns the ids of the applied migrations in order
+func (m VersionedMigrator) Applied() (ids []string, err error) {
+	if err = m.createTable(m.tableName(), &MigrationRecord{}); err == nil {
+		err = m.DB.Table(m.tableName()).Order("id").Pluck("id", &ids).Error
+	}
+	return
+}
+
+// Unlock releases the lock, e.g: left by a crashed instance
+func (m VersionedMigrator) Unlock() error {
+	return m.DB.Table(m.lockTableName()).Where("id = ?", 1).Delete(&MigrationLock{}).Error
+}
+
+func (m VersionedMigrator) migrate(migration *Migration) error {
+	return m.run(func(tx *gorm.DB) error {
+		if err := migration.Migrate(tx); err != nil {
+			return fmt.Errorf("failed to migrate %v, got error: %w", migration.ID, err)
+		}
+		return tx.Table(m.tableName()).Create(&MigrationRecord{ID: migration.ID, AppliedAt: tx.NowFunc()}).Error
+	})
+}
+
+func (m VersionedMigrator) rollback(migration *Migration) error {
+	if migration.Rollback == nil {
+		return fmt.Errorf("%w: %v", ErrIrreversibleMigration, migration.ID)
+	}
+
+	return m.run(func(tx *gorm.DB) error {
+		if err := migration.Rollback(tx); err != nil {
+			return fmt.Errorf("failed to rollback %v, got error: %w", migration.ID, err)
+		}
+		return tx.Table(m.tableName()).Where("id = ?", migration.ID).Delete(&MigrationRecord{}).Error
+	})
+}
+
+// run runs fc in a transaction if the dialect supports transactional DDL
+func (m VersionedMigrator) run(fc func(tx *gorm.DB) error) error {
+	transactional := false
+	if ddl, ok := m.DB.Dialector.(TransactionalDDLInterface); ok {
+		transactional = ddl.SupportsTransactionalDDL()
+	} else {
+		switch m.DB.Dialector.Name() {
+		case "postgres", "sqlite", "sqlserver":
+			transactional = true
+		}
+	}
+
+	if transactional && !m.DisableTransaction {
+		return m.DB.Transaction(fc)
+	}
+	return fc(m.DB)
+}
+
+// withLock runs fc with the lock, the lock is acquired by creating the only record of the lock table,
+// stale lock left by a crashed instance is released by its LockedAt
+func (m VersionedMigrator) withLock(fc func() error) (err error) {
+	if err := m.createTable(m.lockTableName(), &MigrationLock{}); err != nil {
+		return err
+	}
+
+	timeout := m.LockTimeout
+	if timeout == 0 {
+		timeout = time.Minute
+	}
+
+	staleTimeout := m.StaleLockTimeout
+	if staleTimeout == 0 {
+		staleTimeout = DefaultStaleLockTimeout
+	}
+
+	// failing to create the lock record is expected when it is locked by others
+	tx := m.DB.Session(&gorm.Session{Logger: logger.Discard})
+	for deadline := time.Now().Add(timeout); ; {
+		if err := tx.Table(m.lockTableName()).Create(&MigrationLock{ID: 1, LockedAt: m.DB.NowFunc()}).Error; err == nil {
+			break
+		} else if result := tx.Table(m.lockTableName()).Where("id = ? AND locked_at < ?", 1, m.DB.NowFunc().Add(-staleTimeout)).Delete(&MigrationLock{}); result.Error != nil {
+			return result.Error
+		} else if result.RowsAffected > 0 {
+			m.DB.Logger.Warn(m.DB.Statement.Context, "released stale migration lock of table %v", m.lockTableName())
+		} else if time.Now().After(deadline) {
+			return fmt.Errorf("%w: %v", ErrMigrationLocked, err)
+		} else {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	defer func() {
+		if unlockErr := m.Unlock(); err == nil {
+			err = unlockErr
+		}
+	}()
+	return fc()
+}
+
+func (m VersionedMigrator) createTable(name string, value interface{}) error {
+	if migrator := m.DB.Table(name).Migrator(); !migrator.HasTable(name) {
+		// the table might be created by other instances at the same time
+		if err := migrator.CreateTable(value); err != nil && !migrator.HasTable(name) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m VersionedMigrator) sortedMigrations() ([]*Migration, error) {
+	migrations := make([]*Migration, len(m.Migrations))
+	copy(migrations, m.Migrations)
+	sort.SliceStable(migrations, func(i, j int) bool {
+		return migrations[i].ID < migrations[j].ID
+	})
+
+	for idx, migration := range migrations {
+		if migration.ID == "" || (idx > 0 && migrations[idx-1].ID == migration.ID) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidMigrationID, migration.ID)
+		}
+	}
+	return migrations, nil
+}
+
+func (m VersionedMigrator) tableName() string {
+	if m.TableName == "" {
+		return DefaultMigrationTableName
+	}
+	return m.TableName
+}
+
+func (m VersionedMigrator) lockTableName() string {
+	return m.tableName() + "_lock"
+}
@@ -0,0 +1,289 @@
+package dbresolver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Config resolver config, the connections of Sources are used to write, Replicas are used to read
+type Config struct {
+	// Sources defaults to the connection of the registered DB
+	Sources []gorm.Dialector
+	// Replicas defaults to Sources
+	Replicas []gorm.Dialector
+	// Policy replica selection policy, defaults to RandomPolicy
+	Policy Policy
+	datas  []interface{}
+}
+
+// DBResolver read/write splitting plugin, routes queries to replicas and writes to sources,
+// transactions begin on the sources of the global config and stay on them,
+// the connections opened for the configs are closed by Close
+//    db.Use(dbresolver.Register(dbresolver.Config{
+//        Replicas: []gorm.Dialector{mysql.Open("db2_dsn"), mysql.Open("db3_dsn")},
+//    }).Register(dbresolver.Config{
+//        Sources: []gorm.Dialector{mysql.Open("db4_dsn")},
+//        Policy:  &dbresolver.RoundRobinPolicy{},
+//    }, &Order{}, "audit_logs"))
+type DBResolver struct {
+	*gorm.DB
+	configs   []Config
+	resolvers map[string]*resolver
+	global    *resolver
+	opened    []*gorm.DB
+}
+
+type resolver struct {
+	sources  []gorm.ConnPool
+	replicas []gorm.ConnPool
+	policy   Policy
+}
+
+type observation struct {
+	policy    Observer
+	connPool  gorm.ConnPool
+	startedAt time.Time
+}
+
+// Register register config for the models or tables, the config is used for all tables if no model or table given
+func Register(config Config, datas ...interface{}) *DBResolver {
+	return (&DBResolver{}).Register(config, datas...)
+}
+
+// Register register config for the models or tables, should be called before using the plugin
+func (dr *DBResolver) Register(config Config, datas ...interface{}) *DBResolver {
+	config.datas = datas
+	dr.configs = append(dr.configs, config)
+	return dr
+}
+
+// Name plugin name
+func (dr *DBResolver) Name() string {
+	return "gorm:db_resolver"
+}
+
+// Initialize open connections of the configs and register the callbacks
+func (dr *DBResolver) Initialize(db *gorm.DB) error {
+	dr.DB = db
+	dr.resolvers = map[string]*resolver{}
+
+	for _, config := range dr.configs {
+		if err := dr.compileConfig(config); err != nil {
+			dr.Close()
+			return err
+		}
+	}
+
+	// begin transactions on the sources instead of the connection of the registered DB
+	if dr.global != nil && dr.global.sources[0] != db.Config.ConnPool {
+		connPool := &sourceConnPool{ConnPool: db.Config.ConnPool, resolver: dr.global}
+		db.Config.ConnPool = connPool
+		db.Statement.ConnPool = connPool
+	}
+
+	return dr.registerCallbacks()
+}
+
+// Close closes the connections opened for the configs
+func (dr *DBResolver) Close() (err error) {
+	for _, db := range dr.opened {
+		if sqlDB, e := db.DB(); e != nil {
+			err = e
+		} else if e := sqlDB.Close(); e != nil {
+			err = e
+		}
+	}
+	dr.opened = nil
+	return err
+}
+
+func (dr *DBResolver) compileConfig(config Config) (err error) {
+	r := &resolver{policy: config.Policy}
+	if r.policy == nil {
+		r.policy = RandomPolicy{}
+	}
+
+	if r.sources, err = dr.convertToConnPool(config.Sources); err != nil {
+		return err
+	} else if len(r.sources) == 0 {
+		r.sources = []gorm.ConnPool{dr.DB.Config.ConnPool}
+	}
+
+	if r.replicas, err = dr.convertToConnPool(config.Replicas); err != nil {
+		return err
+	} else if len(r.replicas) == 0 {
+		r.replicas = r.sources
+	}
+
+	if len(config.datas) == 0 {
+		dr.global = r
+		return nil
+	}
+
+	for _, data := range config.datas {
+		if table, ok := data.(string); ok {
+			dr.resolvers[table] = r
+		} else {
+			stmt := &gorm.Statement{DB: dr.DB}
+			if err := stmt.Parse(data); err != nil {
+				return err
+			}
+			dr.resolvers[stmt.Table] = r
+		}
+	}
+	return nil
+}
+
+func (dr *DBResolver) convertToConnPool(dialectors []gorm.Dialector) (connPools []gorm.ConnPool, err error) {
+	for _, dialector := range dialectors {
+		db, err := gorm.Open(dialector, &gorm.Config{Logger: dr.DB.Logger, NamingStrategy: dr.DB.NamingStrategy})
+		if err != nil {
+			return nil, fmt.Errorf("failed to open connection of %v, got error: %w", dialector.Name(), err)
+		}
+		dr.opened = append(dr.opened, db)
+		connPools = append(connPools, db.Config.ConnPool)
+	}
+	return
+}
+
+func (dr *DBResolver) registerCallbacks() error {
+	for _, fc := range []func() error{
+		func() error { return dr.Callback().Create().Before("*").Register(dr.Name(), dr.switchSource) },
+		func() error { return dr.Callback().Update().Before("*").Register(dr.Name(), dr.switchSource) },
+		func() error { return dr.Callback().Delete().Before("*").Register(dr.Name(), dr.switchSource) },
+		func() error { return dr.Callback().Raw().Before("*").Register(dr.Name(), dr.switchSource) },
+		func() error { return dr.Callback().Query().Before("*").Register(dr.Name(), dr.switchReplica) },
+		func() error { return dr.Callback().Row().Before("*").Register(dr.Name(), dr.switchReplica) },
+		// observe the query itself only, preloading runs its own queries
+		func() error {
+			return dr.Callback().Query().After("gorm:query").Before("gorm:preload").Register(dr.Name()+":observe", dr.observe)
+		},
+		func() error { return dr.Callback().Row().After("gorm:row").Register(dr.Name()+":observe", dr.observe) },
+	} {
+		if err := fc(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (dr *DBResolver) resolve(stmt *gorm.Statement) *resolver {
+	if r, ok := dr.resolvers[stmt.Table]; ok {
+		return r
+	}
+	return dr.global
+}
+
+func (dr *DBResolver) switchSource(db *gorm.DB) {
+	if !isTransaction(db.Statement.ConnPool) {
+		if r := dr.resolve(db.Statement); r != nil {
+			db.Statement.ConnPool = r.policy.Resolve(r.sources)
+		}
+	}
+}
+
+func (dr *DBResolver) switchReplica(db *gorm.DB) {
+	if isTransaction(db.Statement.ConnPool) || isWriting(db.Statement) {
+		dr.switchSource(db)
+		return
+	}
+
+	if r := dr.resolve(db.Statement); r != nil {
+		db.Statement.ConnPool = r.policy.Resolve(r.replicas)
+
+		if observer, ok := r.policy.(Observer); ok {
+			db.InstanceSet("gorm:db_resolver:observation", observation{policy: observer, connPool: db.Statement.ConnPool, startedAt: time.Now()})
+		}
+	}
+}
+
+func (dr *DBResolver) observe(db *gorm.DB) {
+	if v, ok := db.InstanceGet("gorm:db_resolver:observation"); ok {
+		if o, ok := v.(observation); ok {
+			o.policy.Observe(o.connPool, time.Since(o.startedAt))
+		}
+	}
+}
+
+// sourceConnPool the connection of the registered DB, which begins transactions on the sources
+type sourceConnPool struct {
+	gorm.ConnPool
+	resolver *resolver
+}
+
+// BeginTx begins transaction on the source selected by the policy
+func (pool *sourceConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	if beginner, ok := pool.resolver.policy.Resolve(pool.resolver.sources).(gorm.TxBeginner); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return nil, gorm.ErrInvalidTransaction
+}
+
+// GetDBConn returns the *sql.DB of the registered DB
+func (pool *sourceConnPool) GetDBConn() (*sql.DB, error) {
+	switch connPool := pool.ConnPool.(type) {
+	case *sql.DB:
+		return connPool, nil
+	case gorm.GetDBConnector:
+		return connPool.GetDBConn()
+	}
+	return nil, gorm.ErrInvalidDB
+}
+
+func isTransaction(connPool gorm.ConnPool) bool {
+	_, ok := connPool.(gorm.TxCommitter)
+	return ok
+}
+
+func isWriting(stmt *gorm.Statement) bool {
+	if _, ok := stmt.Clauses[writeName]; ok {
+		return true
+	} else if _, ok := stmt.Clauses[readName]; ok {
+		return false
+	}
+
+	if _, ok := stmt.Clauses["FOR"]; ok {
+		return true
+	}
+
+	// raw sql, only SELECT statements are routed to replicas
+	if rawSQL := strings.TrimSpace(stmt.SQL.String()); rawSQL != "" {
+		return len(rawSQL) < 6 || !strings.EqualFold(rawSQL[:6], "SELECT") ||
+			strings.HasSuffix(strings.ToUpper(rawSQL), "FOR UPDATE")
+	}
+	return false
+}
+
+// Operation hint to route the statement to the sources or the replicas
+//    db.Clauses(dbresolver.Write).First(&user)
+type Operation string
+
+const (
+	Write Operation = "write"
+	Read  Operation = "read"
+
+	writeName = "gorm:db_resolver:write"
+	readName  = "gorm:db_resolver:read"
+)
+
+// ModifyStatement add the hint to the statement
+func (op Operation) ModifyStatement(stmt *gorm.Statement) {
+	switch op {
+	case Write:
+		delete(stmt.Clauses, readName)
+		stmt.Clauses[writeName] = clause.Clause{}
+	case Read:
+		delete(stmt.Clauses, writeName)
+		stmt.Clauses[readName] = clause.Clause{}
+	}
+}
+
+// Build implements clause.Expression interface
+func (op Operation) Build(clause.Builder) {
+}
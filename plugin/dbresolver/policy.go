@@ -0,0 +1,109 @@
+package dbresolver
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Policy selects a connection from the connections
+type Policy interface {
+	Resolve([]gorm.ConnPool) gorm.ConnPool
+}
+
+// Observer policy that observes the latency of the selected replicas
+type Observer interface {
+	Observe(connPool gorm.ConnPool, elapsed time.Duration)
+}
+
+// RandomPolicy selects a connection randomly
+type RandomPolicy struct {
+}
+
+// Resolve selects a connection randomly
+func (RandomPolicy) Resolve(connPools []gorm.ConnPool) gorm.ConnPool {
+	if len(connPools) == 1 {
+		return connPools[0]
+	}
+	return connPools[rand.Intn(len(connPools))]
+}
+
+// RoundRobinPolicy selects the connections in turn
+type RoundRobinPolicy struct {
+	counter uint64
+}
+
+// Resolve selects the next connection
+func (policy *RoundRobinPolicy) Resolve(connPools []gorm.ConnPool) gorm.ConnPool {
+	return connPools[(atomic.AddUint64(&policy.counter, 1)-1)%uint64(len(connPools))]
+}
+
+// LatencyPolicy selects a connection randomly, weighted by the inverse of the moving average latency,
+// connections that have not been observed are selected first
+type LatencyPolicy struct {
+	// Decay weight of the latest observation in the moving average, defaults to 0.2
+	Decay     float64
+	mux       sync.Mutex
+	latencies map[gorm.ConnPool]float64
+}
+
+// Resolve selects a connection weighted by latency
+func (policy *LatencyPolicy) Resolve(connPools []gorm.ConnPool) gorm.ConnPool {
+	if len(connPools) == 1 {
+		return connPools[0]
+	}
+
+	policy.mux.Lock()
+	defer policy.mux.Unlock()
+
+	var (
+		weights = make([]float64, len(connPools))
+		total   float64
+	)
+
+	for idx, connPool := range connPools {
+		latency, ok := policy.latencies[connPool]
+		if !ok {
+			return connPool
+		}
+
+		if latency < 1 {
+			latency = 1
+		}
+		weights[idx] = 1 / latency
+		total += weights[idx]
+	}
+
+	r := rand.Float64() * total
+	for idx, weight := range weights {
+		if r < weight {
+			return connPools[idx]
+		}
+		r -= weight
+	}
+	return connPools[len(connPools)-1]
+}
+
+// Observe updates the moving average latency of the connection
+func (policy *LatencyPolicy) Observe(connPool gorm.ConnPool, elapsed time.Duration) {
+	policy.mux.Lock()
+	defer policy.mux.Unlock()
+
+	if policy.latencies == nil {
+		policy.latencies = map[gorm.ConnPool]float64{}
+	}
+
+	decay := policy.Decay
+	if decay <= 0 || decay > 1 {
+		decay = 0.2
+	}
+
+	if latency, ok := policy.latencies[connPool]; ok {
+		policy.latencies[connPool] = latency*(1-decay) + float64(elapsed)*decay
+	} else {
+		policy.latencies[connPool] = float64(elapsed)
+	}
+}
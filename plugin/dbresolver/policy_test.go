@@ -0,0 +1,58 @@
+package dbresolver_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/plugin/dbresolver"
+)
+
+func TestRoundRobinPolicy(t *testing.T) {
+	connPools := []gorm.ConnPool{&sql.DB{}, &sql.DB{}, &sql.DB{}}
+	policy := &dbresolver.RoundRobinPolicy{}
+
+	for i := 0; i < 6; i++ {
+		if connPool := policy.Resolve(connPools); connPool != connPools[i%3] {
+			t.Errorf("#%v should select connection %v", i, i%3)
+		}
+	}
+}
+
+func TestRandomPolicy(t *testing.T) {
+	connPools := []gorm.ConnPool{&sql.DB{}, &sql.DB{}}
+	selected := map[gorm.ConnPool]int{}
+
+	for i := 0; i < 100; i++ {
+		selected[dbresolver.RandomPolicy{}.Resolve(connPools)]++
+	}
+
+	if len(selected) != 2 {
+		t.Errorf("all connections should be selected, got %v", selected)
+	}
+}
+
+func TestLatencyPolicy(t *testing.T) {
+	connPools := []gorm.ConnPool{&sql.DB{}, &sql.DB{}}
+	policy := &dbresolver.LatencyPolicy{}
+
+	if connPool := policy.Resolve(connPools); connPool != connPools[0] {
+		t.Errorf("connection not observed should be selected first")
+	}
+	policy.Observe(connPools[0], time.Millisecond)
+
+	if connPool := policy.Resolve(connPools); connPool != connPools[1] {
+		t.Errorf("connection not observed should be selected first")
+	}
+	policy.Observe(connPools[1], time.Second)
+
+	selected := map[gorm.ConnPool]int{}
+	for i := 0; i < 1000; i++ {
+		selected[policy.Resolve(connPools)]++
+	}
+
+	if selected[connPools[0]] <= selected[connPools[1]]*10 {
+		t.Errorf("connection with lower latency should be selected more often, got %v", selected)
+	}
+}
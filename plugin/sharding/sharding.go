@@ -0,0 +1,415 @@
+package sharding
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	// ErrMissingShardingKey sharding key not found in the conditions or the created values
+	ErrMissingShardingKey = errors.New("sharding key required")
+	// ErrCrossShards the values belong to different shards
+	ErrCrossShards = errors.New("values belong to different shards")
+	// ErrInvalidShardingKey the sharding key value is not supported by the sharding algorithm
+	ErrInvalidShardingKey = errors.New("invalid sharding key")
+)
+
+// Config sharding config of tables
+type Config struct {
+	// ShardingKey column name of the sharding key, e.g. user_id
+	ShardingKey string
+	// NumberOfShards number of shards, used by the default sharding algorithm
+	NumberOfShards uint
+	// ShardingAlgorithm returns the table suffix of the sharding key value,
+	// defaults to `_` + value % NumberOfShards, zero padded to the width of NumberOfShards-1, e.g. orders_00 ... orders_63
+	ShardingAlgorithm func(value interface{}) (suffix string, err error)
+	// ShardingSuffixes returns suffixes of all shards, required by fan-out queries if ShardingAlgorithm is customized
+	ShardingSuffixes func() []string
+	// PrimaryKeyGenerator generates primary key for created records whose primary key is zero, e.g. (&Snowflake{Node: 1}).Generate
+	PrimaryKeyGenerator func() (int64, error)
+}
+
+// Sharding horizontal sharding plugin, rewrites the table to the shard of the sharding key
+//    db.Use(sharding.Register(sharding.Config{
+//        ShardingKey:         "user_id",
+//        NumberOfShards:      64,
+//        PrimaryKeyGenerator: (&sharding.Snowflake{Node: 1}).Generate,
+//    }, &Order{}))
+//
+//    db.Where("user_id = ?", 10).Find(&orders) // SELECT * FROM orders_10 WHERE user_id = 10
+//    db.Clauses(sharding.FanOut).Where("amount > ?", 100).Find(&orders)
+//
+// Only the table of the statement is rewritten, the limits:
+//  * fan-out queries UNION ALL the shards, the WHERE conditions are pushed down to the shards unless the query has joins
+//  * sharded tables of joins aren't rewritten, join them by querying the shard with the sharding key instead
+//  * preloading sharded tables requires the sharding key in the conditions, so preloading by a foreign key other
+//    than the sharding key returns ErrMissingShardingKey, and preloading owners of different shards returns ErrCrossShards
+type Sharding struct {
+	*gorm.DB
+	configs map[string]Config
+	tables  []interface{}
+}
+
+// Register register config for the models or tables
+func Register(config Config, tables ...interface{}) *Sharding {
+	return (&Sharding{configs: map[string]Config{}}).Register(config, tables...)
+}
+
+// Register register config for the models or tables, should be called before using the plugin
+func (s *Sharding) Register(config Config, tables ...interface{}) *Sharding {
+	for _, table := range tables {
+		if name, ok := table.(string); ok {
+			s.configs[name] = config
+		} else {
+			s.tables = append(s.tables, table)
+			s.configs[fmt.Sprintf("%T", table)] = config
+		}
+	}
+	return s
+}
+
+// Name plugin name
+func (s *Sharding) Name() string {
+	return "gorm:sharding"
+}
+
+// Initialize resolve tables of the models and register the callbacks
+func (s *Sharding) Initialize(db *gorm.DB) error {
+	s.DB = db
+
+	for _, table := range s.tables {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(table); err != nil {
+			return err
+		}
+
+		key := fmt.Sprintf("%T", table)
+		s.configs[stmt.Table] = s.configs[key]
+		delete(s.configs, key)
+	}
+
+	for name, config := range s.configs {
+		if config.ShardingKey == "" {
+			return fmt.Errorf("sharding key of table %v is required", name)
+		} else if config.ShardingAlgorithm == nil && config.NumberOfShards == 0 {
+			return fmt.Errorf("number of shards of table %v is required", name)
+		}
+	}
+
+	for _, fc := range []func() error{
+		func() error { return db.Callback().Create().Before("gorm:create").Register(s.Name(), s.switchTable(false, true)) },
+		func() error { return db.Callback().Update().Before("gorm:update").Register(s.Name(), s.switchTable(false, false)) },
+		func() error { return db.Callback().Delete().Before("gorm:delete").Register(s.Name(), s.switchTable(false, false)) },
+		func() error { return db.Callback().Query().Before("gorm:query").Register(s.Name(), s.switchTable(true, false)) },
+		func() error { return db.Callback().Row().Before("gorm:row").Register(s.Name(), s.switchTable(true, false)) },
+	} {
+		if err := fc(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Sharding) switchTable(isQuery, isCreate bool) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		stmt := db.Statement
+		config, ok := s.configs[stmt.Table]
+		if db.Error != nil || !ok || stmt.SQL.Len() > 0 {
+			return
+		}
+
+		var (
+			values []interface{}
+			err    error
+		)
+
+		if isCreate {
+			values, err = shardingValuesOfRecords(stmt, config, true)
+		} else if values = shardingValuesOfConditions(stmt, config.ShardingKey); len(values) == 0 && !isQuery {
+			values, err = shardingValuesOfRecords(stmt, config, false)
+		}
+
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+
+		if len(values) == 0 {
+			if _, ok := stmt.Clauses[fanOutName]; ok && isQuery {
+				db.AddError(s.fanOut(stmt, config))
+			} else {
+				db.AddError(fmt.Errorf("%w: %v of table %v", ErrMissingShardingKey, config.ShardingKey, stmt.Table))
+			}
+			return
+		}
+
+		var suffix string
+		for idx, value := range values {
+			if valueSuffix, err := config.suffix(value); err != nil {
+				db.AddError(err)
+				return
+			} else if idx > 0 && valueSuffix != suffix {
+				db.AddError(fmt.Errorf("%w: %v", ErrCrossShards, values))
+				return
+			} else {
+				suffix = valueSuffix
+			}
+		}
+
+		stmt.Table = stmt.Table + suffix
+	}
+}
+
+// fanOut query all shards with UNION ALL, the WHERE conditions are pushed down to every shard unless the query has joins
+func (s *Sharding) fanOut(stmt *gorm.Statement, config Config) error {
+	suffixes, err := config.suffixes()
+	if err != nil {
+		return err
+	}
+
+	where := shardBuilder{stmt: stmt}
+	if c, ok := stmt.Clauses["WHERE"]; ok && len(stmt.Joins) == 0 {
+		if _, ok := stmt.Clauses["FROM"]; !ok {
+			where.WriteByte(' ')
+			c.Build(&where)
+			delete(stmt.Clauses, "WHERE")
+		}
+	}
+
+	var (
+		sql  strings.Builder
+		vars = make([]interface{}, 0, len(where.vars)*len(suffixes))
+	)
+	sql.WriteByte('(')
+	for idx, suffix := range suffixes {
+		if idx > 0 {
+			sql.WriteString(" UNION ALL ")
+		}
+		sql.WriteString("SELECT * FROM ")
+		sql.WriteString(stmt.Quote(stmt.Table + suffix))
+		sql.WriteString(" AS ")
+		sql.WriteString(stmt.Quote(stmt.Table))
+		sql.WriteString(where.String())
+		vars = append(vars, where.vars...)
+	}
+	sql.WriteString(") AS ")
+	sql.WriteString(stmt.Quote(stmt.Table))
+
+	stmt.TableExpr = &clause.Expr{SQL: sql.String(), Vars: vars}
+	return nil
+}
+
+// shardBuilder builds the conditions of a shard with placeholders, the vars are bound when building the statement
+type shardBuilder struct {
+	strings.Builder
+	stmt *gorm.Statement
+	vars []interface{}
+}
+
+// WriteQuoted write quoted value
+func (builder *shardBuilder) WriteQuoted(value interface{}) {
+	if table, ok := value.(clause.Table); ok && table.Name == clause.CurrentTable {
+		// the shard is aliased to the table name, don't build the table expression into the statement
+		table.Name = builder.stmt.Table
+		value = table
+	}
+	builder.stmt.QuoteTo(&builder.Builder, value)
+}
+
+// AddVar add vars as placeholders
+func (builder *shardBuilder) AddVar(writer clause.Writer, vars ...interface{}) {
+	for idx, v := range vars {
+		if idx > 0 {
+			writer.WriteByte(',')
+		}
+		writer.WriteByte('?')
+		builder.vars = append(builder.vars, v)
+	}
+}
+
+func (config Config) suffix(value interface{}) (string, error) {
+	if config.ShardingAlgorithm != nil {
+		return config.ShardingAlgorithm(value)
+	}
+
+	reflectValue := reflect.Indirect(reflect.ValueOf(value))
+	switch reflectValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v := reflectValue.Int(); v >= 0 {
+			return config.formatSuffix(uint64(v) % uint64(config.NumberOfShards)), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return config.formatSuffix(reflectValue.Uint() % uint64(config.NumberOfShards)), nil
+	}
+
+	return "", fmt.Errorf("%w: %#v", ErrInvalidShardingKey, value)
+}
+
+func (config Config) suffixes() ([]string, error) {
+	if config.ShardingSuffixes != nil {
+		return config.ShardingSuffixes(), nil
+	} else if config.ShardingAlgorithm != nil {
+		return nil, errors.New("sharding suffixes are required for fan-out queries with customized sharding algorithm")
+	}
+
+	suffixes := make([]string, config.NumberOfShards)
+	for idx := range suffixes {
+		suffixes[idx] = config.formatSuffix(uint64(idx))
+	}
+	return suffixes, nil
+}
+
+func (config Config) formatSuffix(idx uint64) string {
+	width := len(strconv.FormatUint(uint64(config.NumberOfShards)-1, 10))
+	return fmt.Sprintf("_%0*d", width, idx)
+}
+
+var exprRegexp = regexp.MustCompile("^\\s*(?:[\\w\"`]+\\.)?[\"`]?(\\w+)[\"`]?\\s*(=|(?i:IN))\\s*\\(?\\?\\)?\\s*$")
+
+// shardingValuesOfConditions find sharding key values from WHERE conditions, only conditions joined with AND are checked
+func shardingValuesOfConditions(stmt *gorm.Statement, key string) []interface{} {
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok {
+			return shardingValuesOfExprs(stmt, where.Exprs, key)
+		}
+	}
+	return nil
+}
+
+func shardingValuesOfExprs(stmt *gorm.Statement, exprs []clause.Expression, key string) []interface{} {
+	for _, expr := range exprs {
+		if _, ok := expr.(clause.OrConditions); ok {
+			return nil
+		}
+	}
+
+	if stmt.Schema != nil {
+		if field := stmt.Schema.LookUpField(key); field != nil {
+			key = field.DBName
+		}
+	}
+
+	for _, expr := range exprs {
+		var (
+			column interface{}
+			values []interface{}
+		)
+
+		switch v := expr.(type) {
+		case clause.Eq:
+			column, values = v.Column, []interface{}{v.Value}
+		case clause.IN:
+			column, values = v.Column, v.Values
+		case clause.Expr:
+			if matches := exprRegexp.FindStringSubmatch(v.SQL); len(matches) == 3 && len(v.Vars) == 1 {
+				column = matches[1]
+				if !strings.EqualFold(matches[2], "IN") {
+					values = v.Vars
+				} else if rv := reflect.ValueOf(v.Vars[0]); rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+					for i := 0; i < rv.Len(); i++ {
+						values = append(values, rv.Index(i).Interface())
+					}
+				}
+			}
+		case clause.AndConditions:
+			if values := shardingValuesOfExprs(stmt, v.Exprs, key); len(values) > 0 {
+				return values
+			}
+		}
+
+		var name string
+		switch c := column.(type) {
+		case string:
+			name = c[strings.LastIndexByte(c, '.')+1:]
+		case clause.Column:
+			if c.Table == "" || c.Table == clause.CurrentTable || c.Table == stmt.Table {
+				name = c.Name
+			}
+		}
+
+		if stmt.Schema != nil {
+			if field := stmt.Schema.LookUpField(name); field != nil {
+				name = field.DBName
+			}
+		}
+
+		if name == key && len(values) > 0 {
+			return values
+		}
+	}
+	return nil
+}
+
+// shardingValuesOfRecords find sharding key values from the created, updated or deleted records,
+// generates primary keys for the records to create if PrimaryKeyGenerator configured
+func shardingValuesOfRecords(stmt *gorm.Statement, config Config, generateKeys bool) (values []interface{}, err error) {
+	if stmt.Schema == nil {
+		return nil, nil
+	}
+
+	field := stmt.Schema.LookUpField(config.ShardingKey)
+	if field == nil {
+		return nil, fmt.Errorf("%w: %v", gorm.ErrInvalidField, config.ShardingKey)
+	}
+
+	primaryField := stmt.Schema.PrioritizedPrimaryField
+	if primaryField != nil && primaryField.DataType != schema.Int && primaryField.DataType != schema.Uint {
+		primaryField = nil
+	}
+
+	collect := func(reflectValue reflect.Value) error {
+		if generateKeys && config.PrimaryKeyGenerator != nil && primaryField != nil {
+			if _, isZero := primaryField.ValueOf(reflectValue); isZero {
+				if key, err := config.PrimaryKeyGenerator(); err != nil {
+					return err
+				} else if err := primaryField.Set(reflectValue, key); err != nil {
+					return err
+				}
+			}
+		}
+
+		if value, isZero := field.ValueOf(reflectValue); !isZero {
+			values = append(values, value)
+		} else if generateKeys {
+			return fmt.Errorf("%w: %v of table %v", ErrMissingShardingKey, config.ShardingKey, stmt.Table)
+		}
+		return nil
+	}
+
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			if err = collect(reflect.Indirect(stmt.ReflectValue.Index(i))); err != nil {
+				return nil, err
+			}
+		}
+	case reflect.Struct:
+		err = collect(stmt.ReflectValue)
+	}
+	return
+}
+
+// FanOut hint to query all shards if the sharding key is not given
+//    db.Clauses(sharding.FanOut).Where("amount > ?", 100).Find(&orders)
+var FanOut = fanOut{}
+
+const fanOutName = "gorm:sharding:fan_out"
+
+type fanOut struct{}
+
+// ModifyStatement add the hint to the statement
+func (fanOut) ModifyStatement(stmt *gorm.Statement) {
+	stmt.Clauses[fanOutName] = clause.Clause{}
+}
+
+// Build implements clause.Expression interface
+func (fanOut) Build(clause.Builder) {
+}
@@ -0,0 +1,63 @@
+package sharding
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+	snowflakeNodeMask     = 1<<snowflakeNodeBits - 1
+	snowflakeSequenceMask = 1<<snowflakeSequenceBits - 1
+)
+
+// ErrInvalidSnowflakeNode the node of Snowflake is out of range
+var ErrInvalidSnowflakeNode = errors.New("invalid snowflake node")
+
+// DefaultSnowflakeEpoch default epoch of Snowflake
+var DefaultSnowflakeEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Snowflake generates time ordered unique IDs across the shards,
+// an ID is composed of 41 bits milliseconds since Epoch, 10 bits Node and 12 bits sequence
+type Snowflake struct {
+	// Node unique node id of the generator, 0 ~ 1023
+	Node int64
+	// Epoch defaults to DefaultSnowflakeEpoch
+	Epoch time.Time
+
+	mux        sync.Mutex
+	lastMillis int64
+	sequence   int64
+}
+
+// Generate generates a new ID, returns ErrInvalidSnowflakeNode if Node is out of range
+func (snowflake *Snowflake) Generate() (int64, error) {
+	if snowflake.Node < 0 || snowflake.Node > snowflakeNodeMask {
+		return 0, fmt.Errorf("%w: %v, should be 0 ~ %v", ErrInvalidSnowflakeNode, snowflake.Node, snowflakeNodeMask)
+	}
+
+	snowflake.mux.Lock()
+	defer snowflake.mux.Unlock()
+
+	epoch := snowflake.Epoch
+	if epoch.IsZero() {
+		epoch = DefaultSnowflakeEpoch
+	}
+
+	millis := int64(time.Since(epoch) / time.Millisecond)
+	if millis <= snowflake.lastMillis {
+		// same millisecond or the clock moved backwards, keep increasing the sequence
+		millis = snowflake.lastMillis
+		if snowflake.sequence = (snowflake.sequence + 1) & snowflakeSequenceMask; snowflake.sequence == 0 {
+			millis++
+		}
+	} else {
+		snowflake.sequence = 0
+	}
+	snowflake.lastMillis = millis
+
+	return millis<<(snowflakeNodeBits+snowflakeSequenceBits) | snowflake.Node<<snowflakeSequenceBits | snowflake.sequence, nil
+}
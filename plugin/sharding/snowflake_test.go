@@ -0,0 +1,41 @@
+package sharding_test
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm/plugin/sharding"
+)
+
+func TestSnowflake(t *testing.T) {
+	var (
+		snowflake = &sharding.Snowflake{Node: 3}
+		generated = map[int64]bool{}
+		last      int64
+	)
+
+	for i := 0; i < 10000; i++ {
+		id, err := snowflake.Generate()
+		if err != nil {
+			t.Fatalf("failed to generate id, got %v", err)
+		} else if generated[id] {
+			t.Fatalf("duplicated id %v", id)
+		} else if id <= last {
+			t.Fatalf("id should be increasing, got %v after %v", id, last)
+		}
+
+		if node := id >> 12 & 1023; node != 3 {
+			t.Fatalf("invalid node of id %v, got %v", id, node)
+		}
+		generated[id] = true
+		last = id
+	}
+}
+
+func TestSnowflakeInvalidNode(t *testing.T) {
+	for _, node := range []int64{-1, 1024} {
+		if _, err := (&sharding.Snowflake{Node: node}).Generate(); !errors.Is(err, sharding.ErrInvalidSnowflakeNode) {
+			t.Errorf("should return ErrInvalidSnowflakeNode for node %v, got %v", node, err)
+		}
+	}
+}
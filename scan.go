@@ -98,8 +98,7 @@ func Scan(rows *sql.Rows, db *DB, initialized bool) {
 			var (
 				reflectValueType = db.Statement.ReflectValue.Type().Elem()
 				isPtr            = reflectValueType.Kind() == reflect.Ptr
-				fields           = make([]*schema.Field, len(columns))
-				joinFields       [][2]*schema.Field
+				scanner          = &fieldsScanner{fields: make([]*schema.Field, len(columns)), values: values}
 			)
 
 			if isPtr {
@@ -113,31 +112,12 @@ func Scan(rows *sql.Rows, db *DB, initialized bool) {
 					Schema, _ = schema.Parse(db.Statement.Dest, db.cacheStore, db.NamingStrategy)
 				}
 
-				for idx, column := range columns {
-					if field := Schema.LookUpField(column); field != nil && field.Readable {
-						fields[idx] = field
-					} else if names := strings.Split(column, "__"); len(names) > 1 {
-						if rel, ok := Schema.Relationships.Relations[names[0]]; ok {
-							if field := rel.FieldSchema.LookUpField(strings.Join(names[1:], "__")); field != nil && field.Readable {
-								fields[idx] = field
-
-								if len(joinFields) == 0 {
-									joinFields = make([][2]*schema.Field, len(columns))
-								}
-								joinFields[idx] = [2]*schema.Field{rel.Field, field}
-								continue
-							}
-						}
-						values[idx] = &sql.RawBytes{}
-					} else {
-						values[idx] = &sql.RawBytes{}
-					}
-				}
+				scanner = newFieldsScanner(Schema, columns)
 			}
 
 			// pluck values into slice of data
 			isPluck := false
-			if len(fields) == 1 {
+			if len(scanner.fields) == 1 {
 				if _, ok := reflect.New(reflectValueType).Interface().(sql.Scanner); ok || // is scanner
 					reflectValueType.Kind() != reflect.Struct || // is not struct
 					Schema.ModelType.ConvertibleTo(schema.TimeReflectType) { // is time
@@ -153,31 +133,7 @@ func Scan(rows *sql.Rows, db *DB, initialized bool) {
 				if isPluck {
 					db.AddError(rows.Scan(elem.Interface()))
 				} else {
-					for idx, field := range fields {
-						if field != nil {
-							values[idx] = reflect.New(reflect.PtrTo(field.IndirectFieldType)).Interface()
-						}
-					}
-
-					db.AddError(rows.Scan(values...))
-
-					for idx, field := range fields {
-						if len(joinFields) != 0 && joinFields[idx][0] != nil {
-							value := reflect.ValueOf(values[idx]).Elem()
-							relValue := joinFields[idx][0].ReflectValueOf(elem)
-
-							if relValue.Kind() == reflect.Ptr && relValue.IsNil() {
-								if value.IsNil() {
-									continue
-								}
-								relValue.Set(reflect.New(relValue.Type().Elem()))
-							}
-
-							field.Set(relValue, values[idx])
-						} else if field != nil {
-							field.Set(elem, values[idx])
-						}
-					}
+					db.AddError(scanner.scan(rows, elem))
 				}
 
 				if isPtr {
@@ -192,46 +148,8 @@ func Scan(rows *sql.Rows, db *DB, initialized bool) {
 			}
 
 			if initialized || rows.Next() {
-				for idx, column := range columns {
-					if field := Schema.LookUpField(column); field != nil && field.Readable {
-						values[idx] = reflect.New(reflect.PtrTo(field.IndirectFieldType)).Interface()
-					} else if names := strings.Split(column, "__"); len(names) > 1 {
-						if rel, ok := Schema.Relationships.Relations[names[0]]; ok {
-							if field := rel.FieldSchema.LookUpField(strings.Join(names[1:], "__")); field != nil && field.Readable {
-								values[idx] = reflect.New(reflect.PtrTo(field.IndirectFieldType)).Interface()
-								continue
-							}
-						}
-						values[idx] = &sql.RawBytes{}
-					} else {
-						values[idx] = &sql.RawBytes{}
-					}
-				}
-
 				db.RowsAffected++
-				db.AddError(rows.Scan(values...))
-
-				for idx, column := range columns {
-					if field := Schema.LookUpField(column); field != nil && field.Readable {
-						field.Set(db.Statement.ReflectValue, values[idx])
-					} else if names := strings.Split(column, "__"); len(names) > 1 {
-						if rel, ok := Schema.Relationships.Relations[names[0]]; ok {
-							if field := rel.FieldSchema.LookUpField(strings.Join(names[1:], "__")); field != nil && field.Readable {
-								relValue := rel.Field.ReflectValueOf(db.Statement.ReflectValue)
-								value := reflect.ValueOf(values[idx]).Elem()
-
-								if relValue.Kind() == reflect.Ptr && relValue.IsNil() {
-									if value.IsNil() {
-										continue
-									}
-									relValue.Set(reflect.New(relValue.Type().Elem()))
-								}
-
-								field.Set(relValue, values[idx])
-							}
-						}
-					}
-				}
+				db.AddError(newFieldsScanner(Schema, columns).scan(rows, db.Statement.ReflectValue))
 			}
 		}
 	}
@@ -240,3 +158,71 @@ func Scan(rows *sql.Rows, db *DB, initialized bool) {
 		db.AddError(ErrRecordNotFound)
 	}
 }
+
+// fieldsScanner scans rows into struct values, the fields are resolved from the columns once
+type fieldsScanner struct {
+	fields     []*schema.Field
+	joinFields [][2]*schema.Field
+	values     []interface{}
+}
+
+func newFieldsScanner(Schema *schema.Schema, columns []string) *fieldsScanner {
+	scanner := &fieldsScanner{
+		fields: make([]*schema.Field, len(columns)),
+		values: make([]interface{}, len(columns)),
+	}
+
+	for idx, column := range columns {
+		if field := Schema.LookUpField(column); field != nil && field.Readable {
+			scanner.fields[idx] = field
+		} else if names := strings.Split(column, "__"); len(names) > 1 {
+			if rel, ok := Schema.Relationships.Relations[names[0]]; ok {
+				if field := rel.FieldSchema.LookUpField(strings.Join(names[1:], "__")); field != nil && field.Readable {
+					scanner.fields[idx] = field
+
+					if len(scanner.joinFields) == 0 {
+						scanner.joinFields = make([][2]*schema.Field, len(columns))
+					}
+					scanner.joinFields[idx] = [2]*schema.Field{rel.Field, field}
+					continue
+				}
+			}
+			scanner.values[idx] = &sql.RawBytes{}
+		} else {
+			scanner.values[idx] = &sql.RawBytes{}
+		}
+	}
+
+	return scanner
+}
+
+// scan scans current row into the struct value
+func (scanner *fieldsScanner) scan(rows *sql.Rows, reflectValue reflect.Value) error {
+	for idx, field := range scanner.fields {
+		if field != nil {
+			scanner.values[idx] = reflect.New(reflect.PtrTo(field.IndirectFieldType)).Interface()
+		}
+	}
+
+	err := rows.Scan(scanner.values...)
+
+	for idx, field := range scanner.fields {
+		if len(scanner.joinFields) != 0 && scanner.joinFields[idx][0] != nil {
+			value := reflect.ValueOf(scanner.values[idx]).Elem()
+			relValue := scanner.joinFields[idx][0].ReflectValueOf(reflectValue)
+
+			if relValue.Kind() == reflect.Ptr && relValue.IsNil() {
+				if value.IsNil() {
+					continue
+				}
+				relValue.Set(reflect.New(relValue.Type().Elem()))
+			}
+
+			field.Set(relValue, scanner.values[idx])
+		} else if field != nil {
+			field.Set(reflectValue, scanner.values[idx])
+		}
+	}
+
+	return err
+}
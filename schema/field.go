@@ -54,6 +54,7 @@ type Field struct {
 	DefaultValueInterface interface{}
 	NotNull               bool
 	Unique                bool
+	Tenant                bool
 	Comment               string
 	Size                  int
 	Precision             int
@@ -178,6 +179,10 @@ func (schema *Schema) ParseField(fieldStruct reflect.StructField) *Field {
 		field.Unique = true
 	}
 
+	if val, ok := field.TagSettings["TENANT"]; ok && utils.CheckTruth(val) {
+		field.Tenant = true
+	}
+
 	if val, ok := field.TagSettings["COMMENT"]; ok {
 		field.Comment = val
 	}
@@ -19,10 +19,12 @@ type Schema struct {
 	Name                      string
 	ModelType                 reflect.Type
 	Table                     string
+	IsView                    bool
 	PrioritizedPrimaryField   *Field
 	DBNames                   []string
 	PrimaryFields             []*Field
 	PrimaryFieldDBNames       []string
+	TenantField               *Field
 	Fields                    []*Field
 	FieldsByName              map[string]*Field
 	FieldsByDBName            map[string]*Field
@@ -37,6 +39,8 @@ type Schema struct {
 	BeforeDelete, AfterDelete bool
 	BeforeSave, AfterSave     bool
 	AfterFind                 bool
+	AfterCommit               bool
+	AfterRollback             bool
 	err                       error
 	namer                     Namer
 	cacheStore                *sync.Map
@@ -70,6 +74,11 @@ type Tabler interface {
 	TableName() string
 }
 
+// Viewer model mapped onto a view, which is queryable but won't be created as a table by the migrator
+type Viewer interface {
+	IsView() bool
+}
+
 // get data type from dialector
 func Parse(dest interface{}, cacheStore *sync.Map, namer Namer) (*Schema, error) {
 	if dest == nil {
@@ -101,10 +110,16 @@ func Parse(dest interface{}, cacheStore *sync.Map, namer Namer) (*Schema, error)
 		tableName = en.Table
 	}
 
+	var isView bool
+	if viewer, ok := modelValue.Interface().(Viewer); ok {
+		isView = viewer.IsView()
+	}
+
 	schema := &Schema{
 		Name:           modelType.Name(),
 		ModelType:      modelType,
 		Table:          tableName,
+		IsView:         isView,
 		FieldsByName:   map[string]*Field{},
 		FieldsByDBName: map[string]*Field{},
 		Relationships:  Relationships{Relations: map[string]*Relationship{}},
@@ -154,6 +169,13 @@ func Parse(dest interface{}, cacheStore *sync.Map, namer Namer) (*Schema, error)
 				if field.PrimaryKey {
 					schema.PrimaryFields = append(schema.PrimaryFields, field)
 				}
+
+				if field.Tenant {
+					if schema.TenantField != nil && schema.TenantField != v {
+						schema.err = fmt.Errorf("duplicate tenant fields %v and %v of %v", schema.TenantField.Name, field.Name, schema.Name)
+					}
+					schema.TenantField = field
+				}
 			}
 		}
 
@@ -219,6 +241,17 @@ func Parse(dest interface{}, cacheStore *sync.Map, namer Namer) (*Schema, error)
 		}
 	}
 
+	for _, name := range []string{"AfterCommit", "AfterRollback"} {
+		if methodValue := modelValue.MethodByName(name); methodValue.IsValid() {
+			switch methodValue.Type().String() {
+			case "func(*gorm.DB)":
+				reflect.Indirect(reflect.ValueOf(schema)).FieldByName(name).SetBool(true)
+			default:
+				logger.Default.Warn(context.Background(), "Model %v don't match %vInterface, should be %v(*gorm.DB)", schema, name, name)
+			}
+		}
+	}
+
 	if _, loaded := cacheStore.LoadOrStore(modelType, schema); !loaded {
 		if _, embedded := schema.cacheStore.Load(embeddedCacheKey); !embedded {
 			for _, field := range schema.Fields {
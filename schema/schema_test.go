@@ -297,3 +297,29 @@ func TestEmbeddedStructForCustomizedNamingStrategy(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTenantField(t *testing.T) {
+	type TenantModel struct {
+		ID       uint
+		TenantID uint `gorm:"tenant"`
+	}
+
+	tenantSchema, err := schema.Parse(&TenantModel{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse tenant model, got error %v", err)
+	}
+
+	if field := tenantSchema.TenantField; field == nil || field.Name != "TenantID" || !field.Tenant {
+		t.Errorf("tenant field should be parsed, got %+v", field)
+	}
+
+	type DuplicateTenantModel struct {
+		ID          uint
+		TenantID    uint `gorm:"tenant"`
+		OrgTenantID uint `gorm:"tenant"`
+	}
+
+	if _, err := schema.Parse(&DuplicateTenantModel{}, &sync.Map{}, schema.NamingStrategy{}); err == nil || !strings.Contains(err.Error(), "duplicate tenant fields") {
+		t.Errorf("should return error for duplicate tenant fields, got %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"reflect"
+	"time"
 
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/schema"
@@ -50,6 +51,8 @@ func (DeletedAt) QueryClauses(f *schema.Field) []clause.Interface {
 
 type SoftDeleteQueryClause struct {
 	Field *schema.Field
+	// AliveValue the value of the records not deleted, nil checks IS NULL
+	AliveValue interface{}
 }
 
 func (sd SoftDeleteQueryClause) Name() string {
@@ -64,23 +67,31 @@ func (sd SoftDeleteQueryClause) MergeClause(*clause.Clause) {
 
 func (sd SoftDeleteQueryClause) ModifyStatement(stmt *Statement) {
 	if _, ok := stmt.Clauses["soft_delete_enabled"]; !ok {
-		if c, ok := stmt.Clauses["WHERE"]; ok {
-			if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) > 1 {
-				for _, expr := range where.Exprs {
-					if orCond, ok := expr.(clause.OrConditions); ok && len(orCond.Exprs) == 1 {
+		groupOrConditions(stmt)
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sd.Field.DBName}, Value: sd.AliveValue},
+		}})
+		stmt.Clauses["soft_delete_enabled"] = clause.Clause{}
+	}
+}
+
+// groupOrConditions groups the WHERE conditions if there are OR conditions, so that the conditions added later apply to all of them
+func groupOrConditions(stmt *Statement) {
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok {
+			for _, expr := range where.Exprs {
+				if orCond, ok := expr.(clause.OrConditions); ok && len(orCond.Exprs) == 1 {
+					if len(where.Exprs) == 1 {
+						where.Exprs = orCond.Exprs
+					} else {
 						where.Exprs = []clause.Expression{clause.And(where.Exprs...)}
-						c.Expression = where
-						stmt.Clauses["WHERE"] = c
-						break
 					}
+					c.Expression = where
+					stmt.Clauses["WHERE"] = c
+					break
 				}
 			}
 		}
-
-		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
-			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sd.Field.DBName}, Value: nil},
-		}})
-		stmt.Clauses["soft_delete_enabled"] = clause.Clause{}
 	}
 }
 
@@ -90,6 +101,12 @@ func (DeletedAt) DeleteClauses(f *schema.Field) []clause.Interface {
 
 type SoftDeleteDeleteClause struct {
 	Field *schema.Field
+	// AliveValue the value of the records not deleted, nil checks IS NULL
+	AliveValue interface{}
+	// DeletedValue returns the value of the deleted records, defaults to the deleting time
+	DeletedValue func(now time.Time) interface{}
+	// DeletedAtField the field set to the deleting time together with Field, e.g: deleted flag with deleted time
+	DeletedAtField *schema.Field
 }
 
 func (sd SoftDeleteDeleteClause) Name() string {
@@ -104,7 +121,19 @@ func (sd SoftDeleteDeleteClause) MergeClause(*clause.Clause) {
 
 func (sd SoftDeleteDeleteClause) ModifyStatement(stmt *Statement) {
 	if stmt.SQL.String() == "" {
-		stmt.AddClause(clause.Set{{Column: clause.Column{Name: sd.Field.DBName}, Value: stmt.DB.NowFunc()}})
+		now := stmt.DB.NowFunc()
+		set := clause.Set{{Column: clause.Column{Name: sd.Field.DBName}, Value: now}}
+		if sd.DeletedValue != nil {
+			set[0].Value = sd.DeletedValue(now)
+		}
+
+		if sd.DeletedAtField != nil {
+			set = append(set, clause.Assignment{Column: clause.Column{Name: sd.DeletedAtField.DBName}, Value: now})
+			if sd.DeletedAtField.GORMDataType != schema.Time {
+				set[1].Value = now.Unix()
+			}
+		}
+		stmt.AddClause(set)
 
 		if stmt.Schema != nil {
 			_, queryValues := schema.GetIdentityFieldValuesMap(stmt.ReflectValue, stmt.Schema.PrimaryFields)
@@ -127,10 +156,85 @@ func (sd SoftDeleteDeleteClause) ModifyStatement(stmt *Statement) {
 		if _, ok := stmt.Clauses["WHERE"]; !stmt.DB.AllowGlobalUpdate && !ok {
 			stmt.DB.AddError(ErrMissingWhereClause)
 		} else {
-			SoftDeleteQueryClause{Field: sd.Field}.ModifyStatement(stmt)
+			if stmt.Schema != nil && stmt.Schema.TenantField != nil {
+				TenantQueryClause{Field: stmt.Schema.TenantField}.ModifyStatement(stmt)
+			}
+			SoftDeleteQueryClause{Field: sd.Field, AliveValue: sd.AliveValue}.ModifyStatement(stmt)
 		}
 
 		stmt.AddClauseIfNotExists(clause.Update{})
-		stmt.Build("UPDATE", "SET", "WHERE")
+		stmt.Build("WITH", "UPDATE", "SET", "WHERE", "RETURNING")
+	}
+}
+
+// RestoreValues returns the values to restore the soft deleted records
+func (sd SoftDeleteDeleteClause) RestoreValues() map[string]interface{} {
+	values := map[string]interface{}{sd.Field.DBName: sd.AliveValue}
+	if sd.DeletedAtField != nil {
+		values[sd.DeletedAtField.DBName] = nil
+		if sd.DeletedAtField.GORMDataType != schema.Time {
+			values[sd.DeletedAtField.DBName] = 0
+		}
 	}
+	return values
+}
+
+// DeletedAtUnix soft delete with unix seconds, 0 means not deleted, it can be used in unique indexes as it is never NULL
+type DeletedAtUnix int64
+
+func (DeletedAtUnix) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteQueryClause{Field: f, AliveValue: 0}}
+}
+
+func (DeletedAtUnix) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteDeleteClause{Field: f, AliveValue: 0, DeletedValue: func(now time.Time) interface{} {
+		return now.Unix()
+	}}}
+}
+
+// DeletedAtUnixMilli soft delete with unix milliseconds, 0 means not deleted
+type DeletedAtUnixMilli int64
+
+func (DeletedAtUnixMilli) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteQueryClause{Field: f, AliveValue: 0}}
+}
+
+func (DeletedAtUnixMilli) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteDeleteClause{Field: f, AliveValue: 0, DeletedValue: func(now time.Time) interface{} {
+		return now.UnixNano() / 1e6
+	}}}
+}
+
+// DeletedFlag soft delete with boolean flag, the field named by tag `deletedAtField` is set to the deleting time together
+//    type User struct {
+//      ID        uint
+//      IsDeleted gorm.DeletedFlag `gorm:"deletedAtField:DeletedAt"`
+//      DeletedAt *time.Time
+//    }
+type DeletedFlag bool
+
+// Scan implements the Scanner interface.
+func (n *DeletedFlag) Scan(value interface{}) error {
+	var flag sql.NullBool
+	err := flag.Scan(value)
+	*n = DeletedFlag(flag.Bool)
+	return err
+}
+
+// Value implements the driver Valuer interface.
+func (n DeletedFlag) Value() (driver.Value, error) {
+	return bool(n), nil
+}
+
+func (DeletedFlag) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteQueryClause{Field: f, AliveValue: false}}
+}
+
+func (DeletedFlag) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteDeleteClause{
+		Field:          f,
+		AliveValue:     false,
+		DeletedValue:   func(time.Time) interface{} { return true },
+		DeletedAtField: f.Schema.LookUpField(f.TagSettings["DELETEDATFIELD"]),
+	}}
 }
@@ -43,6 +43,7 @@ type Statement struct {
 	CurDestIndex         int
 	attrs                []interface{}
 	assigns              []interface{}
+	raw                  clause.Expression // the raw SQL of Raw before building, rebuilt when used as a subquery
 }
 
 type join struct {
@@ -181,6 +182,8 @@ func (stmt *Statement) AddVar(writer clause.Writer, vars ...interface{}) {
 		case []byte:
 			stmt.Vars = append(stmt.Vars, v)
 			stmt.DB.Dialector.BindVarTo(writer, stmt, v)
+		case clause.Expression:
+			v.Build(stmt)
 		case []interface{}:
 			if len(v) > 0 {
 				writer.WriteByte('(')
@@ -192,9 +195,21 @@ func (stmt *Statement) AddVar(writer clause.Writer, vars ...interface{}) {
 		case *DB:
 			subdb := v.Session(&Session{Logger: logger.Discard, DryRun: true, WithConditions: true}).getInstance()
 			subdb.Statement.Vars = append(subdb.Statement.Vars, stmt.Vars...)
-			subdb.callbacks.Query().Execute(subdb)
+			if v.Statement.raw != nil {
+				// raw subquery, rebuild it with the vars of current statement
+				v.Statement.raw.Build(subdb.Statement)
+			} else {
+				subdb.callbacks.Query().Execute(subdb)
+			}
+
 			writer.WriteString(subdb.Statement.SQL.String())
 			stmt.Vars = subdb.Statement.Vars
+
+			if v.Error != nil {
+				stmt.AddError(v.Error)
+			} else if subdb.Error != nil {
+				stmt.AddError(subdb.Error)
+			}
 		default:
 			switch rv := reflect.ValueOf(v); rv.Kind() {
 			case reflect.Slice, reflect.Array:
@@ -297,6 +312,11 @@ func (stmt *Statement) BuildCondition(query interface{}, args ...interface{}) []
 			sort.Strings(keys)
 
 			for _, key := range keys {
+				if subquery, ok := v[key].(*DB); ok {
+					conds = append(conds, clause.Expr{SQL: "? IN (?)", Vars: []interface{}{clause.Column{Name: key}, subquery}})
+					continue
+				}
+
 				reflectValue := reflect.Indirect(reflect.ValueOf(v[key]))
 				switch reflectValue.Kind() {
 				case reflect.Slice, reflect.Array:
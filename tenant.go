@@ -0,0 +1,126 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+type tenantContextKey struct{}
+
+// WithTenant returns a copy of ctx with the tenant id, which is used to scope the models that have a tenant field
+//    type Order struct {
+//        ID       uint
+//        TenantID uint `gorm:"tenant"`
+//    }
+//
+//    db.WithContext(gorm.WithTenant(ctx, 1)).Find(&orders) // SELECT * FROM orders WHERE orders.tenant_id = 1
+func WithTenant(ctx context.Context, tenantID interface{}) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant id of ctx
+func TenantFromContext(ctx context.Context) (interface{}, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	tenantID := ctx.Value(tenantContextKey{})
+	return tenantID, tenantID != nil
+}
+
+// TenantQueryClause adds the tenant condition to the statement
+type TenantQueryClause struct {
+	Field *schema.Field
+}
+
+func (tc TenantQueryClause) Name() string {
+	return ""
+}
+
+func (tc TenantQueryClause) Build(clause.Builder) {
+}
+
+func (tc TenantQueryClause) MergeClause(*clause.Clause) {
+}
+
+func (tc TenantQueryClause) ModifyStatement(stmt *Statement) {
+	if _, ok := stmt.Clauses["tenant_enabled"]; !ok {
+		tenantID, ok := TenantFromContext(stmt.Context)
+		if !ok {
+			stmt.DB.AddError(ErrMissingTenant)
+			return
+		}
+
+		groupOrConditions(stmt)
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: tc.Field.DBName}, Value: tenantID},
+		}})
+		stmt.Clauses["tenant_enabled"] = clause.Clause{}
+	}
+}
+
+// TenantCreateClause sets the tenant field of the created values, the conflicting rows of upserts are only updated if
+// they belong to the tenant, upserts are rejected if the dialect builds ON CONFLICT without the conditions, e.g: MySQL
+type TenantCreateClause struct {
+	Field *schema.Field
+}
+
+func (tc TenantCreateClause) Name() string {
+	return ""
+}
+
+func (tc TenantCreateClause) Build(clause.Builder) {
+}
+
+func (tc TenantCreateClause) MergeClause(*clause.Clause) {
+}
+
+func (tc TenantCreateClause) ModifyStatement(stmt *Statement) {
+	tenantID, ok := TenantFromContext(stmt.Context)
+	if !ok {
+		stmt.DB.AddError(ErrMissingTenant)
+		return
+	}
+
+	if c, ok := stmt.Clauses["ON CONFLICT"]; ok || stmt.UpdatingColumn {
+		onConflict, _ := c.Expression.(clause.OnConflict)
+		if !onConflict.DoNothing && (stmt.UpdatingColumn || onConflict.UpdateAll || len(onConflict.DoUpdates) > 0) {
+			if _, ok := stmt.DB.ClauseBuilders[onConflict.Name()]; ok {
+				stmt.AddError(fmt.Errorf("%w: upsert of tenant model %v isn't supported by the dialect", ErrInvalidData, stmt.Schema.Name))
+				return
+			}
+
+			onConflict.UpdateWhere.Exprs = append(append([]clause.Expression{}, onConflict.UpdateWhere.Exprs...), clause.Eq{
+				Column: clause.Column{Table: clause.CurrentTable, Name: tc.Field.DBName}, Value: tenantID,
+			})
+			stmt.AddClause(onConflict)
+		}
+	}
+
+	switch value := stmt.Dest.(type) {
+	case map[string]interface{}:
+		delete(value, tc.Field.Name)
+		value[tc.Field.DBName] = tenantID
+	case *map[string]interface{}:
+		delete(*value, tc.Field.Name)
+		(*value)[tc.Field.DBName] = tenantID
+	case []map[string]interface{}:
+		for _, v := range value {
+			delete(v, tc.Field.Name)
+			v[tc.Field.DBName] = tenantID
+		}
+	default:
+		switch stmt.ReflectValue.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < stmt.ReflectValue.Len(); i++ {
+				stmt.AddError(tc.Field.Set(stmt.ReflectValue.Index(i), tenantID))
+			}
+		case reflect.Struct:
+			stmt.AddError(tc.Field.Set(stmt.ReflectValue, tenantID))
+		}
+	}
+}
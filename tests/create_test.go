@@ -133,6 +133,131 @@ func TestBulkCreateWithAssociations(t *testing.T) {
 	}
 }
 
+func TestCreateInBatches(t *testing.T) {
+	users := []User{
+		*GetUser("create_in_batches_1", Config{Account: true, Pets: 2, Toys: 3, Company: true, Manager: true, Team: 0, Languages: 1, Friends: 1}),
+		*GetUser("create_in_batches_2", Config{Account: false, Pets: 2, Toys: 4, Company: false, Manager: false, Team: 1, Languages: 3, Friends: 5}),
+		*GetUser("create_in_batches_3", Config{Account: true, Pets: 0, Toys: 3, Company: true, Manager: false, Team: 4, Languages: 0, Friends: 1}),
+		*GetUser("create_in_batches_4", Config{Account: true, Pets: 3, Toys: 0, Company: false, Manager: true, Team: 0, Languages: 3, Friends: 0}),
+		*GetUser("create_in_batches_5", Config{Account: false, Pets: 0, Toys: 3, Company: true, Manager: false, Team: 1, Languages: 3, Friends: 1}),
+	}
+
+	if results := DB.CreateInBatches(&users, 2); results.Error != nil {
+		t.Fatalf("errors happened when create in batches: %v", results.Error)
+	} else if results.RowsAffected != int64(len(users)) {
+		t.Fatalf("rows affected expects: %v, got %v", len(users), results.RowsAffected)
+	}
+
+	var userIDs []uint
+	for _, user := range users {
+		if user.ID == 0 {
+			t.Fatalf("failed to fill user's ID, got %v", user.ID)
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+
+	var users2 []User
+	DB.Preload("Account").Preload("Pets").Preload("Toys").Preload("Company").Preload("Manager").Preload("Team").Preload("Languages").Preload("Friends").Find(&users2, "id IN ?", userIDs)
+	for idx, user := range users2 {
+		CheckUser(t, user, users[idx])
+	}
+}
+
+func TestCreateInBatchesWithBatchSize(t *testing.T) {
+	users := []User{
+		*GetUser("create_batch_size_1", Config{}),
+		*GetUser("create_batch_size_2", Config{}),
+		*GetUser("create_batch_size_3", Config{}),
+	}
+
+	if results := DB.Session(&gorm.Session{CreateBatchSize: 2}).Create(&users); results.Error != nil {
+		t.Fatalf("errors happened when create with batch size: %v", results.Error)
+	} else if results.RowsAffected != int64(len(users)) {
+		t.Fatalf("rows affected expects: %v, got %v", len(users), results.RowsAffected)
+	}
+
+	for _, user := range users {
+		var result User
+		if err := DB.First(&result, user.ID).Error; err != nil {
+			t.Errorf("failed to find created user, got %v", err)
+		} else {
+			CheckUser(t, result, user)
+		}
+	}
+
+	// the failed batch should rollback all batches
+	failedUsers := []User{
+		*GetUser("create_batch_size_failed_1", Config{}),
+		*GetUser("create_batch_size_failed_2", Config{}),
+		*GetUser("create_batch_size_failed_3", Config{}),
+	}
+	failedUsers[2].ID = users[0].ID
+
+	if err := DB.CreateInBatches(&failedUsers, 2).Error; err == nil {
+		t.Fatalf("should failed to create with duplicated primary key")
+	}
+
+	var count int64
+	DB.Model(&User{}).Where("name LIKE ?", "create_batch_size_failed_%").Count(&count)
+	if count != 0 {
+		t.Errorf("all batches should be rolled back, but got %v records", count)
+	}
+
+	if failedUsers[0].ID != 0 || failedUsers[1].ID != 0 || failedUsers[2].ID != users[0].ID {
+		t.Errorf("primary keys of rolled back batches should be reset, got %v, %v, %v", failedUsers[0].ID, failedUsers[1].ID, failedUsers[2].ID)
+	}
+}
+
+var errRetryableBatch = errors.New("retryable batch")
+
+type RetryBatchUser struct {
+	ID     uint
+	Name   string
+	failed *bool
+}
+
+func (u *RetryBatchUser) AfterCreate(tx *gorm.DB) error {
+	if u.failed != nil && !*u.failed {
+		*u.failed = true
+		return errRetryableBatch
+	}
+	return nil
+}
+
+func TestCreateInBatchesWithRetryPolicy(t *testing.T) {
+	DB.Migrator().DropTable(&RetryBatchUser{})
+	if err := DB.AutoMigrate(&RetryBatchUser{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	var (
+		failed bool
+		users  = []RetryBatchUser{{Name: "retry_batch_1"}, {Name: "retry_batch_2"}, {Name: "retry_batch_3", failed: &failed}}
+		db     = DB.Session(&gorm.Session{RetryPolicy: &gorm.RetryPolicy{
+			MaxAttempts: 2,
+			Backoff:     time.Millisecond,
+			Retryable:   func(err error) bool { return errors.Is(err, errRetryableBatch) },
+		}})
+	)
+
+	// the last batch fails in the first attempt
+	results := db.CreateInBatches(&users, 2)
+	if results.Error != nil || !failed {
+		t.Fatalf("should create in batches after retrying, got %v, failed %v", results.Error, failed)
+	}
+
+	if results.RowsAffected != int64(len(users)) {
+		t.Errorf("rows affected of the failed attempt should not be counted, expects %v, got %v", len(users), results.RowsAffected)
+	}
+
+	for _, user := range users {
+		var result RetryBatchUser
+		if err := DB.First(&result, user.ID).Error; err != nil || result.Name != user.Name {
+			t.Errorf("failed to find created user %v, got %v, %+v", user.Name, err, result)
+		}
+	}
+}
+
 func TestBulkCreatePtrDataWithAssociations(t *testing.T) {
 	users := []*User{
 		GetUser("bulk_ptr_1", Config{Account: true, Pets: 2, Toys: 3, Company: true, Manager: true, Team: 0, Languages: 1, Friends: 1}),
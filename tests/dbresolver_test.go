@@ -0,0 +1,125 @@
+package tests_test
+
+import (
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/plugin/dbresolver"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type recordPolicy struct {
+	mux      sync.Mutex
+	resolved []int
+}
+
+func (policy *recordPolicy) Resolve(connPools []gorm.ConnPool) gorm.ConnPool {
+	policy.mux.Lock()
+	defer policy.mux.Unlock()
+	policy.resolved = append(policy.resolved, len(connPools))
+	return connPools[0]
+}
+
+func (policy *recordPolicy) last() int {
+	policy.mux.Lock()
+	defer policy.mux.Unlock()
+	if len(policy.resolved) == 0 {
+		return 0
+	}
+	last := policy.resolved[len(policy.resolved)-1]
+	policy.resolved = nil
+	return last
+}
+
+func TestDBResolver(t *testing.T) {
+	db, err := gorm.Open(DB.Dialector, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open db, got %v", err)
+	}
+
+	globalPolicy, petPolicy := &recordPolicy{}, &recordPolicy{}
+	resolver := dbresolver.Register(dbresolver.Config{
+		Sources:  []gorm.Dialector{DB.Dialector},
+		Replicas: []gorm.Dialector{DB.Dialector, DB.Dialector},
+		Policy:   globalPolicy,
+	}).Register(dbresolver.Config{
+		Replicas: []gorm.Dialector{DB.Dialector, DB.Dialector, DB.Dialector},
+		Policy:   petPolicy,
+	}, &Pet{}, "toys")
+	if err := db.Use(resolver); err != nil {
+		t.Fatalf("failed to use db resolver, got %v", err)
+	}
+
+	user := *GetUser("db_resolver", Config{})
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user, got %v", err)
+	}
+
+	if resolved := globalPolicy.last(); resolved != 1 {
+		t.Errorf("create should use sources, got %v connections", resolved)
+	}
+
+	var result User
+	if err := db.First(&result, user.ID).Error; err != nil {
+		t.Errorf("failed to query user, got %v", err)
+	}
+	CheckUser(t, result, user)
+
+	if resolved := globalPolicy.last(); resolved != 2 {
+		t.Errorf("query should use replicas, got %v connections", resolved)
+	}
+
+	var count int64
+	db.Model(&User{}).Where("name = ?", user.Name).Count(&count)
+	if resolved := globalPolicy.last(); resolved != 2 || count != 1 {
+		t.Errorf("row query should use replicas, got %v connections, count %v", resolved, count)
+	}
+
+	db.Clauses(dbresolver.Write).First(&result, user.ID)
+	if resolved := globalPolicy.last(); resolved != 1 {
+		t.Errorf("query with write hint should use sources, got %v connections", resolved)
+	}
+
+	db.Raw("SELECT * FROM users WHERE id = ?", user.ID).Scan(&result)
+	if resolved := globalPolicy.last(); resolved != 2 {
+		t.Errorf("raw select should use replicas, got %v connections", resolved)
+	}
+
+	db.Exec("UPDATE users SET age = ? WHERE id = ?", 20, user.ID)
+	if resolved := globalPolicy.last(); resolved != 1 {
+		t.Errorf("exec should use sources, got %v connections", resolved)
+	}
+
+	db.Transaction(func(tx *gorm.DB) error {
+		tx.First(&result, user.ID)
+		return nil
+	})
+	if resolved := globalPolicy.last(); resolved != 1 {
+		t.Errorf("transaction should begin on sources and stay on it, got %v connections", resolved)
+	}
+
+	if sqlDB, err := db.DB(); err != nil || sqlDB == nil {
+		t.Errorf("should get the *sql.DB of the registered DB, got %v", err)
+	}
+
+	var pets []Pet
+	db.Find(&pets)
+	if resolved := petPolicy.last(); resolved != 3 {
+		t.Errorf("pets should use configured replicas, got %v connections", resolved)
+	}
+
+	var toys []Toy
+	db.Table("toys").Find(&toys)
+	if resolved := petPolicy.last(); resolved != 3 {
+		t.Errorf("toys should use configured replicas, got %v connections", resolved)
+	}
+
+	if resolved := globalPolicy.last(); resolved != 0 {
+		t.Errorf("global policy should not be used for configured tables, got %v connections", resolved)
+	}
+
+	if err := resolver.Close(); err != nil {
+		t.Errorf("failed to close connections, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package tests_test
 
 import (
 	"errors"
+	"regexp"
 	"testing"
 
 	"gorm.io/gorm"
@@ -181,3 +182,145 @@ func TestDeleteSliceWithAssociations(t *testing.T) {
 		}
 	}
 }
+
+func TestDeleteWithReturning(t *testing.T) {
+	users := []User{*GetUser("delete_returning_1", Config{}), *GetUser("delete_returning_2", Config{})}
+	DB.Create(&users)
+
+	if DB.Dialector.Name() != "postgres" {
+		if err := DB.Clauses(clause.Returning{}).Delete(&users[0]).Error; !errors.Is(err, gorm.ErrUnsupportedReturning) {
+			t.Errorf("should returns ErrUnsupportedReturning, got %v", err)
+		}
+
+		if err := DB.First(&User{}, users[0].ID).Error; err != nil {
+			t.Errorf("should not delete with unsupported returning, got %v", err)
+		}
+		t.Skip("RETURNING is not supported by current dialect")
+	}
+
+	result := DB.Session(&gorm.Session{DryRun: true}).Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).Delete(&users[0])
+	if !regexp.MustCompile(`^UPDATE .users. SET .deleted_at.=.+ WHERE .+ RETURNING .id.$`).MatchString(result.Statement.SQL.String()) {
+		t.Errorf("invalid soft delete SQL with returning, got %v", result.Statement.SQL.String())
+	}
+
+	result = DB.Session(&gorm.Session{DryRun: true}).Clauses(clause.Returning{}).Unscoped().Delete(&users[0])
+	if !regexp.MustCompile(`^DELETE FROM .users. WHERE .+ RETURNING \*$`).MatchString(result.Statement.SQL.String()) {
+		t.Errorf("invalid delete SQL with returning, got %v", result.Statement.SQL.String())
+	}
+
+	var deleted []User
+	if err := DB.Clauses(clause.Returning{}).Where("name LIKE ?", "delete_returning%").Delete(&deleted).Error; err != nil {
+		t.Fatalf("failed to delete with returning, got error %v", err)
+	}
+
+	if len(deleted) != 2 || !deleted[0].DeletedAt.Valid || deleted[0].Age == 0 {
+		t.Errorf("deleted rows should be scanned into destination, got %+v", deleted)
+	}
+}
+
+type CascadeUser struct {
+	ID        uint
+	Name      string
+	Profile   CascadeProfile
+	Orders    []CascadeOrder
+	Tags      []CascadeTag
+	DeletedAt gorm.DeletedAt
+}
+
+type CascadeTag struct {
+	CascadeUserID uint
+	Name          string
+}
+
+type CascadeProfile struct {
+	ID            uint
+	CascadeUserID uint
+}
+
+type CascadeOrder struct {
+	gorm.Model
+	CascadeUserID uint
+	Items         []CascadeOrderItem
+}
+
+type CascadeOrderItem struct {
+	ID             uint
+	CascadeOrderID uint
+}
+
+var deletedCascadeOrders []uint
+
+func (order *CascadeOrder) BeforeDelete(*gorm.DB) error {
+	deletedCascadeOrders = append(deletedCascadeOrders, order.ID)
+	return nil
+}
+
+func TestDeleteWithNestedAssociations(t *testing.T) {
+	DB.Migrator().DropTable(&CascadeUser{}, &CascadeProfile{}, &CascadeOrder{}, &CascadeOrderItem{}, &CascadeTag{})
+	if err := DB.AutoMigrate(&CascadeUser{}, &CascadeProfile{}, &CascadeOrder{}, &CascadeOrderItem{}, &CascadeTag{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	newUser := func(name string) CascadeUser {
+		return CascadeUser{Name: name, Orders: []CascadeOrder{{Items: []CascadeOrderItem{{}, {}}}, {Items: []CascadeOrderItem{{}}}}}
+	}
+
+	user1, user2 := newUser("cascade-1"), newUser("cascade-2")
+	DB.Create(&user1)
+	DB.Create(&user2)
+	DB.Create(&[]CascadeTag{{CascadeUserID: user1.ID, Name: "tag"}, {CascadeUserID: user2.ID, Name: "tag"}})
+
+	deletedCascadeOrders = nil
+	if err := DB.Select("Orders", "Profile", "Tags").Delete(&user1).Error; err != nil {
+		t.Fatalf("failed to delete user, got %v", err)
+	}
+
+	if len(deletedCascadeOrders) != 2 || deletedCascadeOrders[0] != user1.Orders[0].ID || deletedCascadeOrders[1] != user1.Orders[1].ID {
+		t.Errorf("hooks of each association should be called, got %v", deletedCascadeOrders)
+	}
+
+	var count int64
+	if DB.Model(&CascadeProfile{}).Where("cascade_user_id = ?", user1.ID).Count(&count); count != 0 {
+		t.Errorf("profile should be deleted, got %v", count)
+	}
+
+	if DB.Model(&CascadeOrder{}).Where("cascade_user_id = ?", user1.ID).Count(&count); count != 0 {
+		t.Errorf("orders should be deleted, got %v", count)
+	}
+
+	if DB.Model(&CascadeTag{}).Where("cascade_user_id = ?", user1.ID).Count(&count); count != 0 {
+		t.Errorf("tags without primary key should be deleted, got %v", count)
+	}
+
+	if DB.Unscoped().Model(&CascadeOrder{}).Where("cascade_user_id = ?", user1.ID).Count(&count); count != 2 {
+		t.Errorf("orders should be soft deleted, got %v", count)
+	}
+
+	if DB.Model(&CascadeOrderItem{}).Where("cascade_order_id IN ?", []uint{user1.Orders[0].ID, user1.Orders[1].ID}).Count(&count); count != 3 {
+		t.Errorf("items not selected should not be deleted, got %v", count)
+	}
+
+	if err := DB.Select(clause.Associations).Delete(&user2).Error; err != nil {
+		t.Fatalf("failed to delete user, got %v", err)
+	}
+
+	if DB.Model(&CascadeOrderItem{}).Where("cascade_order_id IN ?", []uint{user2.Orders[0].ID, user2.Orders[1].ID}).Count(&count); count != 0 {
+		t.Errorf("nested associations should be deleted, got %v", count)
+	}
+
+	if DB.Model(&CascadeOrderItem{}).Count(&count); count != 3 {
+		t.Errorf("items of other users should not be deleted, got %v", count)
+	}
+
+	if err := DB.Unscoped().Select("Orders", "Orders.Items").Delete(&user1).Error; err != nil {
+		t.Fatalf("failed to delete user, got %v", err)
+	}
+
+	if DB.Unscoped().Model(&CascadeOrder{}).Where("cascade_user_id = ?", user1.ID).Count(&count); count != 0 {
+		t.Errorf("soft deleted orders should be deleted with Unscoped, got %v", count)
+	}
+
+	if DB.Model(&CascadeOrderItem{}).Count(&count); count != 0 {
+		t.Errorf("items of soft deleted orders should be deleted with Unscoped, got %v", count)
+	}
+}
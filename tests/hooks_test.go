@@ -120,6 +120,18 @@ func TestRunCallbacks(t *testing.T) {
 		t.Fatalf("AfterFind callbacks should work with slice, called %v", products[0].AfterFindCallTimes)
 	}
 
+	var product Product
+	iter, err := DB.Where("code = ?", "unique_code").Iterate(&product)
+	if err != nil {
+		t.Fatalf("failed to iterate, got %v", err)
+	}
+	for iter.Next() {
+		if product.AfterFindCallTimes != 2 {
+			t.Fatalf("AfterFind callbacks should work with iterator, called %v", product.AfterFindCallTimes)
+		}
+	}
+	iter.Close()
+
 	DB.Where("Code = ?", "unique_code").First(&p)
 	if !reflect.DeepEqual(p.GetCallTimes(), []int64{1, 2, 1, 1, 0, 0, 0, 0, 2}) {
 		t.Fatalf("After update callbacks values are not saved, %v", p.GetCallTimes())
@@ -472,3 +484,75 @@ func TestFailedToSaveAssociationShouldRollback(t *testing.T) {
 		t.Errorf("should find product, but got error %v", err)
 	}
 }
+
+type Product5 struct {
+	gorm.Model
+	Name  string
+	Price int64
+}
+
+var product5Events []string
+
+func (p *Product5) AfterCreate(*gorm.DB) error {
+	if p.Price < 0 {
+		return errors.New("invalid price")
+	}
+	return nil
+}
+
+func (p *Product5) AfterCommit(tx *gorm.DB) {
+	if err := tx.First(&Product5{}, "name = ?", p.Name).Error; err != nil {
+		product5Events = append(product5Events, "failed to find "+p.Name)
+	}
+	product5Events = append(product5Events, "commit:"+p.Name)
+}
+
+func (p *Product5) AfterRollback(*gorm.DB) {
+	product5Events = append(product5Events, "rollback:"+p.Name)
+}
+
+func TestTransactionHooks(t *testing.T) {
+	DB.Migrator().DropTable(&Product5{})
+	DB.AutoMigrate(&Product5{})
+
+	checkEvents := func(t *testing.T, expects ...string) {
+		if strings.Join(product5Events, ",") != strings.Join(expects, ",") {
+			t.Errorf("invalid transaction hooks, expects %v, got %v", expects, product5Events)
+		}
+		product5Events = nil
+	}
+
+	DB.Create(&Product5{Name: "p1"})
+	checkEvents(t, "commit:p1")
+
+	if err := DB.Create(&Product5{Name: "p2", Price: -1}).Error; err == nil {
+		t.Errorf("should failed to create product")
+	}
+	checkEvents(t, "rollback:p2")
+
+	if err := DB.Transaction(func(tx *gorm.DB) error {
+		tx.Create(&Product5{Name: "p3"})
+		tx.OnCommit(func() { product5Events = append(product5Events, "on_commit") })
+		tx.OnRollback(func() { product5Events = append(product5Events, "on_rollback") })
+
+		tx.Transaction(func(tx2 *gorm.DB) error {
+			tx2.Create(&Product5{Name: "p4"})
+			return errors.New("rollback")
+		})
+		checkEvents(t, "rollback:p4")
+		return nil
+	}); err != nil {
+		t.Errorf("no error should return, but got %v", err)
+	}
+	checkEvents(t, "commit:p3", "on_commit")
+
+	tx := DB.Begin()
+	tx.Create(&Product5{Name: "p5"})
+	tx.OnRollback(func() { product5Events = append(product5Events, "on_rollback") })
+	checkEvents(t)
+	tx.Rollback()
+	checkEvents(t, "rollback:p5", "on_rollback")
+
+	DB.OnCommit(func() { product5Events = append(product5Events, "on_commit") })
+	checkEvents(t, "on_commit")
+}
@@ -1,12 +1,15 @@
 package tests_test
 
 import (
+	"database/sql"
+	"errors"
 	"math/rand"
 	"strings"
 	"testing"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/migrator"
 	. "gorm.io/gorm/utils/tests"
 )
 
@@ -323,3 +326,512 @@ func TestMigrateColumns(t *testing.T) {
 		t.Fatalf("Found deleted column")
 	}
 }
+
+type VersionedMigrationUser struct {
+	ID   uint
+	Name string
+	Age  int
+}
+
+func TestVersionedMigrator(t *testing.T) {
+	DB.Migrator().DropTable(&VersionedMigrationUser{}, migrator.DefaultMigrationTableName, migrator.DefaultMigrationTableName+"_lock")
+
+	var calls []string
+	m := migrator.VersionedMigrator{DB: DB, LockTimeout: 300 * time.Millisecond, Migrations: []*migrator.Migration{{
+		ID: "202010160002_add_age",
+		Migrate: func(tx *gorm.DB) error {
+			calls = append(calls, "migrate 2")
+			return tx.Migrator().AddColumn(&VersionedMigrationUser{}, "Age")
+		},
+		Rollback: func(tx *gorm.DB) error {
+			calls = append(calls, "rollback 2")
+			return tx.Migrator().DropColumn(&VersionedMigrationUser{}, "Age")
+		},
+	}, {
+		ID: "202010160001_create_users",
+		Migrate: func(tx *gorm.DB) error {
+			calls = append(calls, "migrate 1")
+			return tx.Table("versioned_migration_users").Migrator().CreateTable(&struct {
+				ID   uint
+				Name string
+			}{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			calls = append(calls, "rollback 1")
+			return tx.Migrator().DropTable(&VersionedMigrationUser{})
+		},
+	}, {
+		ID: "202010160003_seed_users",
+		Migrate: func(tx *gorm.DB) error {
+			calls = append(calls, "migrate 3")
+			return tx.Create(&VersionedMigrationUser{Name: "jinzhu", Age: 18}).Error
+		},
+	}}}
+
+	checkApplied := func(t *testing.T, expects ...string) {
+		if applied, err := m.Applied(); err != nil || strings.Join(applied, ",") != strings.Join(expects, ",") {
+			t.Errorf("applied migrations expects %v, got %v, %v", expects, applied, err)
+		}
+	}
+
+	if err := m.MigrateTo("202010160002_add_age"); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+	checkApplied(t, "202010160001_create_users", "202010160002_add_age")
+
+	if !DB.Migrator().HasColumn(&VersionedMigrationUser{}, "Age") {
+		t.Errorf("column age should be added")
+	}
+
+	if err := m.Migrate(); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+	checkApplied(t, "202010160001_create_users", "202010160002_add_age", "202010160003_seed_users")
+
+	if err := m.Migrate(); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	if strings.Join(calls, ",") != "migrate 1,migrate 2,migrate 3" {
+		t.Errorf("migrations should be applied once in order, got %v", calls)
+	}
+
+	if err := m.Rollback(1); !errors.Is(err, migrator.ErrIrreversibleMigration) {
+		t.Errorf("should return ErrIrreversibleMigration, got %v", err)
+	}
+
+	m.Migrations[2].Rollback = func(tx *gorm.DB) error {
+		calls = append(calls, "rollback 3")
+		return tx.Where("name = ?", "jinzhu").Delete(&VersionedMigrationUser{}).Error
+	}
+
+	if err := m.Rollback(2); err != nil {
+		t.Fatalf("failed to rollback, got %v", err)
+	}
+	checkApplied(t, "202010160001_create_users")
+
+	if DB.Migrator().HasColumn(&VersionedMigrationUser{}, "Age") {
+		t.Errorf("column age should be dropped")
+	}
+
+	if err := m.MigrateTo("unknown"); !errors.Is(err, migrator.ErrUnknownMigration) {
+		t.Errorf("should return ErrUnknownMigration, got %v", err)
+	}
+
+	if err := DB.Table(migrator.DefaultMigrationTableName + "_lock").Create(&migrator.MigrationLock{ID: 1, LockedAt: DB.NowFunc()}).Error; err != nil {
+		t.Fatalf("failed to lock, got %v", err)
+	}
+
+	if err := m.Migrate(); !errors.Is(err, migrator.ErrMigrationLocked) {
+		t.Errorf("should return ErrMigrationLocked when locked by others, got %v", err)
+	}
+
+	if err := m.Unlock(); err != nil {
+		t.Errorf("failed to unlock, got %v", err)
+	}
+
+	if err := DB.Table(migrator.DefaultMigrationTableName + "_lock").Create(&migrator.MigrationLock{ID: 1, LockedAt: DB.NowFunc().Add(-2 * time.Hour)}).Error; err != nil {
+		t.Fatalf("failed to lock, got %v", err)
+	}
+
+	if err := m.Migrate(); err != nil {
+		t.Errorf("stale lock should be released, got %v", err)
+	}
+	checkApplied(t, "202010160001_create_users", "202010160002_add_age", "202010160003_seed_users")
+
+	if err := m.Rollback(2); err != nil {
+		t.Fatalf("failed to rollback, got %v", err)
+	}
+
+	var count int64
+	if DB.Table(migrator.DefaultMigrationTableName + "_lock").Count(&count); count != 0 {
+		t.Errorf("lock should be released after migrating, got %v", count)
+	}
+
+	m.Migrations[0].Migrate = func(tx *gorm.DB) error {
+		tx.Migrator().AddColumn(&VersionedMigrationUser{}, "Age")
+		return errors.New("failed")
+	}
+
+	if err := m.Migrate(); err == nil || !strings.Contains(err.Error(), "202010160002_add_age") {
+		t.Errorf("should return error of the migration, got %v", err)
+	}
+	checkApplied(t, "202010160001_create_users")
+
+	if DB.Migrator().HasColumn(&VersionedMigrationUser{}, "Age") {
+		t.Errorf("failed migration should be rollbacked")
+	}
+}
+
+func TestPlanAutoMigrate(t *testing.T) {
+	type PlanUser struct {
+		ID   uint
+		Name string `gorm:"size:64"`
+	}
+
+	DB.Migrator().DropTable(&PlanUser{})
+
+	plan, err := DB.PlanAutoMigrate(&PlanUser{})
+	if err != nil {
+		t.Fatalf("failed to plan migration, got %v", err)
+	}
+
+	if len(plan.Steps) != 1 || !strings.Contains(strings.ToUpper(plan.Steps[0].SQL), "CREATE TABLE") || plan.Steps[0].Reason != "table not found" {
+		t.Fatalf("should plan to create table, got %#v", plan.Steps)
+	}
+
+	if DB.Migrator().HasTable(&PlanUser{}) {
+		t.Fatalf("planning migration should not create table")
+	}
+
+	if err := DB.AutoMigrate(&PlanUser{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	type PlanUser2 struct {
+		ID   uint
+		Name string `gorm:"size:256;not null;index:idx_plan_users_name"`
+		Age  int
+	}
+
+	plan, err = DB.Table("plan_users").PlanAutoMigrate(&PlanUser2{})
+	if err != nil {
+		t.Fatalf("failed to plan migration, got %v", err)
+	}
+
+	reasons := map[string]bool{}
+	for _, step := range plan.Steps {
+		reasons[step.Reason] = true
+	}
+
+	for _, reason := range []string{"column age not found", "index idx_plan_users_name not found"} {
+		if !reasons[reason] {
+			t.Errorf("should plan step with reason %q, got %#v", reason, plan.Steps)
+		}
+	}
+
+	// sqlite doesn't report the size of columns, all reasons of the column are reported
+	if DB.Dialector.Name() != "sqlite" && !reasons["column name size 64 != 256; column name should be not null"] {
+		t.Errorf("should plan to alter column name, got %#v", plan.Steps)
+	}
+
+	if DB.Table("plan_users").Migrator().HasColumn(&PlanUser2{}, "Age") || DB.Table("plan_users").Migrator().HasIndex(&PlanUser2{}, "Name") {
+		t.Errorf("planning migration should not change table")
+	}
+}
+
+type AdultUser struct {
+	ID   uint
+	Name string
+	Age  uint
+}
+
+func (AdultUser) TableName() string {
+	return "adult_users"
+}
+
+func (AdultUser) IsView() bool {
+	return true
+}
+
+func TestMigrateView(t *testing.T) {
+	DB.Migrator().DropView("adult_users")
+
+	if err := DB.Migrator().CreateView("adult_users", gorm.ViewOption{}); !errors.Is(err, gorm.ErrSubQueryRequired) {
+		t.Errorf("should returns ErrSubQueryRequired without query, got %v", err)
+	}
+
+	if err := DB.AutoMigrate(&AdultUser{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	if DB.Migrator().HasTable(&AdultUser{}) {
+		t.Fatalf("should not create table for view")
+	}
+
+	checker, ok := DB.Migrator().(gorm.ViewChecker)
+	if !ok {
+		t.Fatalf("migrator should implement gorm.ViewChecker")
+	}
+
+	users := []User{*GetUser("view_user_1", Config{}), *GetUser("view_user_2", Config{}), *GetUser("view_user_o'neil", Config{})}
+	users[0].Age, users[1].Age, users[2].Age = 17, 30, 40
+	DB.Create(&users)
+
+	invalidQuery := DB.Model(&User{}).Select("id", "name", "age").Where("name = ?", []byte("view_user_1"))
+	if err := DB.Migrator().CreateView("adult_users", gorm.ViewOption{Query: invalidQuery}); !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("should returns ErrInvalidData for binary vars, got %v", err)
+	}
+
+	invalidQuery = DB.Model(&User{}).Select("id", "name", "age").Where("name = ?", "view_user\\' OR 1=1 --")
+	if err := DB.Migrator().CreateView("adult_users", gorm.ViewOption{Query: invalidQuery}); !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("should returns ErrInvalidData for strings contain backslashes, got %v", err)
+	}
+
+	if DB.Error != nil || checker.HasView("adult_users") {
+		t.Fatalf("failed views should not be created, got %v", DB.Error)
+	}
+
+	query := DB.Model(&User{}).Select("id", "name", "age").Where("name LIKE ? AND age > ? AND name <> ?", "view_user_%", 18, "view_user_o'neil")
+	if err := DB.Migrator().CreateView("adult_users", gorm.ViewOption{Query: query}); err != nil {
+		t.Fatalf("failed to create view, got %v", err)
+	}
+
+	if !checker.HasView("adult_users") {
+		t.Errorf("should find the created view")
+	}
+
+	var adults []AdultUser
+	if err := DB.Find(&adults).Error; err != nil {
+		t.Fatalf("failed to query view, got %v", err)
+	}
+
+	if len(adults) != 1 || adults[0].Name != "view_user_2" || adults[0].ID != users[1].ID {
+		t.Errorf("should find adult users from view, got %+v", adults)
+	}
+
+	// sqlite doesn't support CREATE OR REPLACE VIEW
+	if DB.Dialector.Name() != "sqlite" {
+		query = DB.Model(&User{}).Select("id", "name", "age").Where("name LIKE ? AND age > ? AND name <> ?", "view_user_%", 10, "view_user_o'neil")
+		if err := DB.Migrator().CreateView("adult_users", gorm.ViewOption{Query: query, Replace: true}); err != nil {
+			t.Fatalf("failed to replace view, got %v", err)
+		}
+
+		if err := DB.Find(&adults).Error; err != nil || len(adults) != 2 {
+			t.Errorf("should find users from replaced view, got %v, %+v", err, adults)
+		}
+	}
+
+	if err := DB.Migrator().DropView("adult_users"); err != nil {
+		t.Fatalf("failed to drop view, got %v", err)
+	}
+
+	if checker.HasView("adult_users") {
+		t.Errorf("should not find the dropped view")
+	}
+
+	if err := DB.Find(&adults).Error; err == nil {
+		t.Errorf("should failed to query dropped view")
+	}
+}
+
+func TestMigrateColumnTypes(t *testing.T) {
+	// the pinned mysql and postgres drivers override ColumnTypes without gorm.ColumnTypeExtended
+	extendedSupported := map[string]bool{"sqlite": true, "sqlserver": true}[DB.Dialector.Name()]
+
+	type ColumnTypeUser struct {
+		ID   uint
+		Name string `gorm:"size:64"`
+		Code string
+	}
+
+	DB.Migrator().DropTable(&ColumnTypeUser{})
+	if err := DB.AutoMigrate(&ColumnTypeUser{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	columnTypes, err := DB.Migrator().ColumnTypes(&ColumnTypeUser{})
+	if err != nil {
+		t.Fatalf("failed to get column types, got %v", err)
+	}
+
+	for _, ct := range columnTypes {
+		columnType, ok := ct.(gorm.ColumnTypeExtended)
+		if !ok {
+			if extendedSupported {
+				t.Errorf("column %v should implement gorm.ColumnTypeExtended", ct.Name())
+			}
+			continue
+		}
+
+		if _, ok := columnType.ColumnType(); !ok {
+			t.Errorf("column %v should have column type", columnType.Name())
+		}
+
+		switch columnType.Name() {
+		case "id":
+			if pk, ok := columnType.PrimaryKey(); ok && !pk {
+				t.Errorf("id should be primary key")
+			}
+			if autoIncrement, ok := columnType.AutoIncrement(); ok && !autoIncrement {
+				t.Errorf("id should be auto increment")
+			}
+		case "name":
+			if unique, ok := columnType.Unique(); ok && unique {
+				t.Errorf("name should not be unique")
+			}
+			if dv, ok := columnType.DefaultValue(); ok && dv != "NULL" {
+				t.Errorf("name should not have default value, got %v", dv)
+			}
+		}
+	}
+
+	// AlterColumn of the other dialects doesn't apply the default value and unique
+	if name := DB.Dialector.Name(); name != "mysql" && name != "sqlite" {
+		return
+	}
+
+	type ColumnTypeUser2 struct {
+		ID   uint
+		Name string `gorm:"size:64;default:jinzhu"`
+		Code string `gorm:"unique"`
+	}
+
+	stmt := &gorm.Statement{DB: DB}
+	if err := stmt.Parse(&ColumnTypeUser2{}); err != nil {
+		t.Fatalf("failed to parse schema, got %v", err)
+	}
+
+	// column types without default value and unique constraint
+	for _, columnType := range columnTypes {
+		ct := migrator.ColumnType{
+			NameValue:         sql.NullString{String: columnType.Name(), Valid: true},
+			DataTypeValue:     sql.NullString{String: columnType.DatabaseTypeName(), Valid: true},
+			DefaultValueValue: sql.NullString{String: "NULL", Valid: true},
+			UniqueValue:       sql.NullBool{Bool: columnType.Name() == "id", Valid: true},
+		}
+
+		if field := stmt.Schema.LookUpField(columnType.Name()); !field.PrimaryKey {
+			if err := DB.Table("column_type_users").Migrator().MigrateColumn(&ColumnTypeUser2{}, field, ct); err != nil {
+				t.Fatalf("failed to migrate column %v, got %v", field.Name, err)
+			}
+		}
+	}
+
+	if err := DB.Exec("INSERT INTO column_type_users (code) VALUES (?)", "code").Error; err != nil {
+		t.Fatalf("failed to insert, got %v", err)
+	}
+
+	var user ColumnTypeUser2
+	if err := DB.Table("column_type_users").First(&user, "code = ?", "code").Error; err != nil || user.Name != "jinzhu" {
+		t.Errorf("default value of name should be migrated, got %v, %+v", err, user)
+	}
+
+	if err := DB.Exec("INSERT INTO column_type_users (code) VALUES (?)", "code").Error; err == nil {
+		t.Errorf("code should be migrated to unique")
+	}
+}
+
+func TestMigrateColumnDataType(t *testing.T) {
+	type DataTypeUser struct {
+		ID     uint
+		Age    int64  `gorm:"type:smallint"`
+		Code   string `gorm:"type:varchar(10)"`
+		Score  int64  `gorm:"type:int4"`
+		Active bool
+	}
+
+	DB.Migrator().DropTable(&DataTypeUser{})
+	if err := DB.AutoMigrate(&DataTypeUser{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	type DataTypeUser2 struct {
+		ID     uint
+		Age    int64  `gorm:"type:bigint"`
+		Code   string `gorm:"type:varchar(10)"`
+		Score  int64  `gorm:"type:integer"`
+		Active bool
+	}
+
+	plan, err := DB.Table("data_type_users").PlanAutoMigrate(&DataTypeUser2{})
+	if err != nil {
+		t.Fatalf("failed to plan migration, got %v", err)
+	}
+
+	if len(plan.Steps) == 0 {
+		t.Fatalf("should plan to alter column age")
+	}
+
+	for _, step := range plan.Steps {
+		if step.Reason != "column age type smallint != bigint" {
+			t.Errorf("should only alter column age, got %#v", step)
+		}
+	}
+
+	if err := DB.Table("data_type_users").AutoMigrate(&DataTypeUser2{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	columnTypes, err := DB.Table("data_type_users").Migrator().ColumnTypes(&DataTypeUser2{})
+	if err != nil {
+		t.Fatalf("failed to get column types, got %v", err)
+	}
+
+	expects := map[string][]string{"age": {"bigint", "int8"}, "code": {"varchar", "character varying"}, "score": {"int4", "integer", "int"}}
+	for _, columnType := range columnTypes {
+		if types, ok := expects[columnType.Name()]; ok {
+			var matched bool
+			for _, typ := range types {
+				matched = matched || strings.HasPrefix(strings.ToLower(columnType.DatabaseTypeName()), typ)
+			}
+
+			if !matched {
+				t.Errorf("column %v's type should be %v, got %v", columnType.Name(), types, columnType.DatabaseTypeName())
+			}
+		}
+	}
+
+	// bool is stored as tinyint(1) by MySQL
+	if plan, err := DB.Table("data_type_users").PlanAutoMigrate(&DataTypeUser2{}); err != nil || len(plan.Steps) != 0 {
+		t.Errorf("should not alter migrated columns, got %v, %#v", err, plan)
+	}
+
+	type DataTypeUser3 struct {
+		ID     uint
+		Age    int64 `gorm:"type:bigint"`
+		Code   int64 `gorm:"type:integer;not null"`
+		Score  int64 `gorm:"type:integer"`
+		Active bool
+	}
+
+	err = DB.Table("data_type_users").AutoMigrate(&DataTypeUser3{})
+	if !errors.Is(err, gorm.ErrIncompatibleDataType) {
+		t.Fatalf("should returns ErrIncompatibleDataType, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "column code type varchar can't be changed to integer") {
+		t.Errorf("should report the incompatible type, got %v", err)
+	}
+
+	if DB.Dialector.Name() != "sqlite" && !strings.Contains(err.Error(), "column code should be not null") {
+		t.Errorf("should report the other reasons of the column, got %v", err)
+	}
+
+	type UnknownTypeUser struct {
+		ID       uint
+		Birthday string `gorm:"type:date"`
+	}
+
+	DB.Migrator().DropTable(&UnknownTypeUser{})
+	if err := DB.AutoMigrate(&UnknownTypeUser{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	// data types not listed in the aliases can't be compared, skip them instead of failing the migration
+	type UnknownTypeUser2 struct {
+		ID       uint
+		Birthday string `gorm:"type:datetime"`
+	}
+
+	if plan, err := DB.Table("unknown_type_users").PlanAutoMigrate(&UnknownTypeUser2{}); err != nil || len(plan.Steps) != 0 {
+		t.Errorf("should skip columns of unknown data types, got %v, %#v", err, plan)
+	}
+
+	if DB.Dialector.Name() == "postgres" {
+		type ArrayUser struct {
+			ID   uint
+			Tags string `gorm:"type:text[]"`
+		}
+
+		DB.Migrator().DropTable(&ArrayUser{})
+		if err := DB.AutoMigrate(&ArrayUser{}); err != nil {
+			t.Fatalf("failed to migrate, got %v", err)
+		}
+
+		// the data type of text[] is reported as _text
+		if plan, err := DB.PlanAutoMigrate(&ArrayUser{}); err != nil || len(plan.Steps) != 0 {
+			t.Errorf("should not alter array columns, got %v, %#v", err, plan)
+		}
+	}
+}
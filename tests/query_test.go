@@ -2,6 +2,7 @@ package tests_test
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -278,6 +279,183 @@ func TestFindInBatches(t *testing.T) {
 	}
 }
 
+func TestFindInKeysetBatches(t *testing.T) {
+	var users = []User{
+		*GetUser("find_in_keyset_batches", Config{}),
+		*GetUser("find_in_keyset_batches", Config{}),
+		*GetUser("find_in_keyset_batches", Config{}),
+		*GetUser("find_in_keyset_batches", Config{}),
+		*GetUser("find_in_keyset_batches", Config{}),
+	}
+
+	for idx := range users {
+		users[idx].Age = uint(5 - idx%2)
+	}
+
+	DB.Create(&users)
+
+	var (
+		results    []User
+		foundIDs   []uint
+		totalBatch int
+	)
+
+	if result := DB.Where("name = ?", users[0].Name).Order("id").FindInKeysetBatches(&results, 2, func(tx *gorm.DB, batch int) error {
+		totalBatch += batch
+
+		for _, user := range results {
+			foundIDs = append(foundIDs, user.ID)
+		}
+
+		if batch == 1 {
+			// records created during the iteration with larger keys should be found as well
+			user := *GetUser("find_in_keyset_batches", Config{})
+			if err := tx.Session(&gorm.Session{}).Create(&user).Error; err != nil {
+				return err
+			}
+			users = append(users, user)
+		}
+
+		return nil
+	}); result.Error != nil || result.RowsAffected != 6 {
+		t.Errorf("Failed to batch find, got error %v, rows affected: %v", result.Error, result.RowsAffected)
+	}
+
+	if totalBatch != 6 {
+		t.Errorf("incorrect total batch, expects: %v, got %v", 6, totalBatch)
+	}
+
+	for idx, user := range users {
+		if idx >= len(foundIDs) || foundIDs[idx] != user.ID {
+			t.Fatalf("records should be ordered by primary key, expects: %v, got %v", user.ID, foundIDs)
+		}
+	}
+
+	type KeysetUser struct {
+		ID   uint
+		Name string
+		Age  uint `gorm:"not null"`
+	}
+
+	DB.Migrator().DropTable(&KeysetUser{})
+	if err := DB.AutoMigrate(&KeysetUser{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	keysetUsers := []KeysetUser{{Name: "keyset", Age: 5}, {Name: "keyset", Age: 4}, {Name: "keyset", Age: 5}, {Name: "keyset", Age: 3}, {Name: "keyset", Age: 4}, {Name: "keyset", Age: 5}}
+	DB.Create(&keysetUsers)
+
+	var (
+		ages          []uint
+		keysetResults []KeysetUser
+	)
+	if result := DB.Where("name = ?", "keyset").FindInKeysetBatches(&keysetResults, 2, func(tx *gorm.DB, batch int) error {
+		for _, user := range keysetResults {
+			ages = append(ages, user.Age)
+		}
+		return nil
+	}, "Age", "ID"); result.Error != nil || result.RowsAffected != 6 {
+		t.Errorf("Failed to batch find with columns, got error %v, rows affected: %v", result.Error, result.RowsAffected)
+	}
+
+	if !sort.SliceIsSorted(ages, func(i, j int) bool { return ages[i] < ages[j] }) || len(ages) != 6 {
+		t.Errorf("records should be ordered by age, got %v", ages)
+	}
+
+	if err := DB.Where("name = ?", users[0].Name).FindInKeysetBatches(&results, 2, func(tx *gorm.DB, batch int) error {
+		return nil
+	}, "Age", "ID").Error; !errors.Is(err, gorm.ErrInvalidField) {
+		t.Errorf("should return ErrInvalidField for nullable column, got %v", err)
+	}
+
+	if err := DB.Order("age").FindInKeysetBatches(&results, 2, func(tx *gorm.DB, batch int) error {
+		return nil
+	}).Error; !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("should return ErrInvalidData for conflicting order, got %v", err)
+	}
+
+	if err := DB.FindInKeysetBatches(&results, 0, func(tx *gorm.DB, batch int) error {
+		return nil
+	}).Error; !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("should return ErrInvalidData for invalid batch size, got %v", err)
+	}
+
+	if err := DB.FindInKeysetBatches(&results, 2, func(tx *gorm.DB, batch int) error {
+		return nil
+	}, "unknown").Error; !errors.Is(err, gorm.ErrInvalidField) {
+		t.Errorf("should return ErrInvalidField for unknown column, got %v", err)
+	}
+}
+
+func TestIterate(t *testing.T) {
+	var users = []User{
+		*GetUser("iterate", Config{Account: true, Pets: 2}),
+		*GetUser("iterate", Config{Account: true}),
+		*GetUser("iterate", Config{Pets: 1}),
+	}
+
+	DB.Create(&users)
+
+	var user User
+	iter, err := DB.Where("name = ?", users[0].Name).Order("id").Iterate(&user)
+	if err != nil {
+		t.Fatalf("failed to iterate, got error %v", err)
+	}
+	defer iter.Close()
+
+	var idx int
+	for iter.Next() {
+		if idx >= len(users) {
+			t.Fatalf("iterated more records than created")
+		}
+
+		expected := users[idx]
+		expected.Account = Account{}
+		expected.Pets = nil
+		CheckUser(t, user, expected)
+		idx++
+	}
+
+	if err := iter.Err(); err != nil {
+		t.Errorf("no error should happen when iterating, got %v", err)
+	}
+
+	if idx != len(users) || iter.RowsAffected() != int64(len(users)) {
+		t.Errorf("should iterate all records, expects: %v, got %v, rows affected: %v", len(users), idx, iter.RowsAffected())
+	}
+
+	if err := iter.Close(); err != nil {
+		t.Errorf("failed to close iterator, got %v", err)
+	}
+
+	var result struct {
+		Name string
+		Age  uint
+	}
+
+	iter, err = DB.Model(&User{}).Where("name = ?", users[0].Name).Order("id").Iterate(&result)
+	if err != nil {
+		t.Fatalf("failed to iterate with smaller struct, got error %v", err)
+	}
+	defer iter.Close()
+
+	idx = 0
+	for iter.Next() {
+		if result.Name != users[idx].Name || result.Age != users[idx].Age {
+			t.Errorf("invalid result, expects: %v, got %+v", users[idx], result)
+		}
+		idx++
+	}
+
+	if idx != len(users) {
+		t.Errorf("should iterate all records, expects: %v, got %v", len(users), idx)
+	}
+
+	if _, err := DB.Model(&User{}).Iterate(&[]User{}); !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("should return ErrInvalidData when iterating into slice, got %v", err)
+	}
+}
+
 func TestFillSmallerStruct(t *testing.T) {
 	user := User{Name: "SmallerUser", Age: 100}
 	DB.Save(&user)
@@ -821,6 +999,76 @@ func TestSubQueryWithRaw(t *testing.T) {
 	}
 }
 
+func TestSubQueryAsValueAndSource(t *testing.T) {
+	users := []User{
+		{Name: "subquery_value_1", Age: 10},
+		{Name: "subquery_value_2", Age: 20},
+		{Name: "subquery_value_3", Age: 30},
+		{Name: "subquery_value_4", Age: 40},
+	}
+	DB.Create(&users)
+
+	var results []User
+	if err := DB.Where("name LIKE ?", "subquery_value%").Where("age > (?)", DB.Raw("SELECT AVG(age) FROM users WHERE name LIKE ?", "subquery_value%")).Find(&results).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Errorf("Two users should be found with raw subquery, instead found %d", len(results))
+	}
+
+	results = nil
+	if err := DB.Where("name LIKE ?", "subquery_value%").Where("age > (?)", DB.Raw("SELECT AVG(age) FROM users WHERE name LIKE ? AND '$1' <> '?'", "subquery_value%")).Find(&results).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Errorf("Two users should be found with raw subquery containing literals, instead found %d", len(results))
+	}
+
+	rawResult := DB.Session(&gorm.Session{DryRun: true}).Where("name = ?", "jinzhu").Where("age > (?)", DB.Raw("SELECT @@max_age")).Find(&[]User{})
+	if !strings.Contains(rawResult.Statement.SQL.String(), "(SELECT @@max_age)") {
+		t.Errorf("raw subquery should be kept as it is, got %v", rawResult.Statement.SQL.String())
+	}
+
+	if !reflect.DeepEqual(rawResult.Statement.Vars, []interface{}{"jinzhu"}) {
+		t.Errorf("invalid vars of raw subquery, got %v", rawResult.Statement.Vars)
+	}
+
+	results = nil
+	if err := DB.Where(map[string]interface{}{"name": DB.Model(&User{}).Select("name").Where("name LIKE ? AND age >= ?", "subquery_value%", 30)}).Find(&results).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Errorf("Two users should be found with subquery in map conditions, instead found %d", len(results))
+	}
+
+	var count int64
+	if err := DB.Table("(?) as u", DB.Model(&User{}).Select("name", "age").Where("name LIKE ?", "subquery_value%")).Where("u.age >= ?", 20).Count(&count).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if count != 3 {
+		t.Errorf("Three users should be found with subquery as source, instead found %d", count)
+	}
+
+	result := DB.Session(&gorm.Session{DryRun: true}).Table("(?) as u", DB.Model(&User{}).Select("name").Where("age > ?", 20)).Where("u.name = ?", "jinzhu").Find(&[]map[string]interface{}{})
+	if !regexp.MustCompile(`SELECT \* FROM \(SELECT .name. FROM .users. WHERE age > .+ AND .users.\..deleted_at. IS NULL\) as u WHERE u.name = .+`).MatchString(result.Statement.SQL.String()) {
+		t.Errorf("invalid subquery as source SQL, got %v", result.Statement.SQL.String())
+	}
+
+	if !reflect.DeepEqual(result.Statement.Vars, []interface{}{20, "jinzhu"}) {
+		t.Errorf("invalid vars, got %v", result.Statement.Vars)
+	}
+
+	subQuery := DB.Model(&User{}).Select("name")
+	subQuery.AddError(gorm.ErrInvalidField)
+	if err := DB.Where("name IN (?)", subQuery).Find(&results).Error; !errors.Is(err, gorm.ErrInvalidField) {
+		t.Errorf("should return error of subquery, but got %v", err)
+	}
+}
+
 func TestSubQueryWithHaving(t *testing.T) {
 	users := []User{
 		{Name: "subquery_having_1", Age: 10},
@@ -839,6 +1087,261 @@ func TestSubQueryWithHaving(t *testing.T) {
 	}
 }
 
+func TestWith(t *testing.T) {
+	users := []User{
+		{Name: "with_1", Age: 10},
+		{Name: "with_2", Age: 20},
+		{Name: "with_3", Age: 30},
+		{Name: "with_4", Age: 40},
+	}
+	DB.Create(&users)
+
+	var results []User
+	if err := DB.With("with_adults", DB.Model(&User{}).Where("name LIKE ? AND age >= ?", "with_%", 20)).
+		Table("with_adults").Order("age").Find(&results).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if len(results) != 3 || results[0].Name != "with_2" {
+		t.Errorf("Three users should be found, instead found %+v", results)
+	}
+
+	var nums []int
+	if err := DB.Clauses(clause.With{Recursive: true, CTEs: []clause.CTE{{
+		Name:     "nums",
+		Columns:  []string{"n"},
+		Subquery: clause.Expr{SQL: "SELECT 1 UNION ALL SELECT n+1 FROM nums WHERE n < ?", Vars: []interface{}{5}},
+	}}}).Table("nums").Pluck("n", &nums).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if !reflect.DeepEqual(nums, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("should get numbers from recursive cte, but got %v", nums)
+	}
+
+	if err := DB.With("with_olds", DB.Model(&User{}).Select("id").Where("name LIKE ? AND age >= ?", "with_%", 30)).
+		Model(&User{}).Where("id IN (SELECT id FROM with_olds)").Update("age", 99).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	var count int64
+	DB.Model(&User{}).Where("name LIKE ? AND age = ?", "with_%", 99).Count(&count)
+	if count != 2 {
+		t.Errorf("Two users should be updated, instead got %v", count)
+	}
+
+	result := DB.Session(&gorm.Session{DryRun: true}).With("with_adults", "SELECT * FROM users WHERE age >= ?", 18).Table("with_adults").Find(&results)
+	if !regexp.MustCompile(`^WITH .with_adults. AS \(SELECT \* FROM users WHERE age >= .+\) SELECT \* FROM .with_adults.`).MatchString(result.Statement.SQL.String()) {
+		t.Errorf("invalid cte SQL, got %v", result.Statement.SQL.String())
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	users := []User{
+		{Name: "set_operation_1", Age: 10},
+		{Name: "set_operation_2", Age: 20},
+		{Name: "set_operation_3", Age: 30},
+		{Name: "set_operation_4", Age: 40},
+	}
+	DB.Create(&users)
+
+	var results []User
+	if err := DB.Where("name LIKE ? AND age < ?", "set_operation_%", 20).
+		Union(DB.Model(&User{}).Where("name LIKE ? AND age > ?", "set_operation_%", 20)).
+		Order("age desc").Limit(2).Find(&results).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if len(results) != 2 || results[0].Name != "set_operation_4" || results[1].Name != "set_operation_3" {
+		t.Errorf("invalid union results, got %+v", results)
+	}
+
+	// the query of set operation has its own ORDER BY and LIMIT
+	results = nil
+	if err := DB.Where("name LIKE ? AND age < ?", "set_operation_%", 20).
+		Union(DB.Model(&User{}).Where("name LIKE ?", "set_operation_%").Order("age desc").Limit(1)).
+		Order("age").Find(&results).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if len(results) != 2 || results[0].Name != "set_operation_1" || results[1].Name != "set_operation_4" {
+		t.Errorf("invalid union results with limited query, got %+v", results)
+	}
+
+	var count int64
+	if err := DB.Model(&User{}).Where("name LIKE ? AND age < ?", "set_operation_%", 20).
+		Union(DB.Model(&User{}).Where("name LIKE ? AND age > ?", "set_operation_%", 20)).
+		Count(&count).Error; err != nil || count != 3 {
+		t.Errorf("should count the rows of the whole union, got %v, %v", err, count)
+	}
+
+	var names []string
+	if err := DB.Model(&User{}).Select("name").Where("name LIKE ?", "set_operation_%").
+		UnionAll(DB.Model(&User{}).Select("name").Where("name = ?", "set_operation_1")).
+		Pluck("name", &names).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if len(names) != 5 {
+		t.Errorf("five names should be found with union all, got %v", names)
+	}
+
+	names = nil
+	if err := DB.Model(&User{}).Select("name").Where("name LIKE ?", "set_operation_%").
+		Except(DB.Model(&User{}).Select("name").Where("age >= ?", 20)).
+		Pluck("name", &names).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if !reflect.DeepEqual(names, []string{"set_operation_1"}) {
+		t.Errorf("invalid except results, got %v", names)
+	}
+
+	names = nil
+	if err := DB.Model(&User{}).Select("name").Where("name LIKE ?", "set_operation_%").
+		Intersect(DB.Model(&User{}).Select("name").Where("age > ?", 30)).
+		Pluck("name", &names).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if !reflect.DeepEqual(names, []string{"set_operation_4"}) {
+		t.Errorf("invalid intersect results, got %v", names)
+	}
+
+	result := DB.Session(&gorm.Session{DryRun: true}).Where("age < ?", 20).Union(DB.Model(&User{}).Where("age > ?", 30)).Find(&results)
+	if !regexp.MustCompile(`SELECT \* FROM .users. WHERE age < .+ AND .users.\..deleted_at. IS NULL UNION SELECT \* FROM \(SELECT \* FROM .users. WHERE age > .+ AND .users.\..deleted_at. IS NULL\) AS .set_operand.`).MatchString(result.Statement.SQL.String()) {
+		t.Errorf("invalid union SQL, got %v", result.Statement.SQL.String())
+	}
+
+	if !reflect.DeepEqual(result.Statement.Vars, []interface{}{20, 30}) {
+		t.Errorf("invalid union vars, got %v", result.Statement.Vars)
+	}
+}
+
+func TestWindowFunction(t *testing.T) {
+	users := []User{
+		{Name: "window_1", Age: 10, Active: true},
+		{Name: "window_2", Age: 20, Active: true},
+		{Name: "window_3", Age: 30, Active: true},
+		{Name: "window_4", Age: 40, Active: false},
+		{Name: "window_5", Age: 50, Active: false},
+	}
+	DB.Create(&users)
+
+	var results []User
+	if err := DB.Table("(?) AS u", DB.Model(&User{}).Select("*, ?", clause.Over{
+		Function: clause.RowNumber(),
+		Window: clause.WindowSpec{
+			PartitionBy: []clause.Column{{Name: "active"}},
+			OrderBy:     []clause.OrderByColumn{{Column: clause.Column{Table: clause.CurrentTable, Name: "age"}, Desc: true}},
+		},
+		Alias: "rn",
+	}).Where("name LIKE ?", "window_%")).Where("rn <= ?", 2).Order("age").Find(&results).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if len(results) != 4 || results[0].Name != "window_2" || results[3].Name != "window_5" {
+		t.Errorf("top two users of each group should be found, but got %+v", results)
+	}
+
+	var totals []struct {
+		Name  string
+		Total int
+	}
+	if err := DB.Model(&User{}).Select("name, ?", clause.Over{
+		Function: clause.Sum(clause.Column{Name: "age"}),
+		Window:   clause.WindowSpec{Name: "w"},
+		Alias:    "total",
+	}).Where("name LIKE ?", "window_%").Clauses(clause.Window{Windows: []clause.NamedWindow{{
+		Name: "w", Spec: clause.WindowSpec{
+			OrderBy: []clause.OrderByColumn{{Column: clause.Column{Name: "age"}}},
+			Frame:   &clause.WindowFrame{Start: clause.UnboundedPreceding, End: clause.CurrentRow},
+		},
+	}}}).Order("age").Scan(&totals).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if len(totals) != 5 || totals[1].Total != 30 || totals[4].Total != 150 {
+		t.Errorf("invalid running totals, got %+v", totals)
+	}
+}
+
+type lockingDialector struct {
+	gorm.Dialector
+}
+
+func (lockingDialector) TranslateLocking(locking clause.Locking) (clause.Locking, error) {
+	if locking.Options == clause.LockingOptionsSkipLocked {
+		return locking, fmt.Errorf("%w: %v", gorm.ErrUnsupportedLocking, locking.Options)
+	}
+
+	if locking.Strength == clause.LockingStrengthNoKeyUpdate {
+		locking.Strength = clause.LockingStrengthUpdate
+		return locking, nil
+	}
+
+	return clause.Locking{}, nil
+}
+
+func TestLocking(t *testing.T) {
+	user := *GetUser("locking", Config{})
+	DB.Create(&user)
+
+	switch DB.Dialector.Name() {
+	case "postgres":
+		result := DB.Session(&gorm.Session{DryRun: true}).Clauses(clause.ForUpdate(clause.Table{Name: clause.CurrentTable}).SkipLocked()).Find(&[]User{})
+		if !regexp.MustCompile(`FOR UPDATE OF .users. SKIP LOCKED$`).MatchString(result.Statement.SQL.String()) {
+			t.Errorf("locking should be kept for postgres, got %v", result.Statement.SQL.String())
+		}
+	case "mysql":
+		result := DB.Session(&gorm.Session{DryRun: true}).Clauses(clause.ForNoKeyUpdate().SkipLocked()).Find(&[]User{})
+		if !regexp.MustCompile(`FOR UPDATE SKIP LOCKED$`).MatchString(result.Statement.SQL.String()) {
+			t.Errorf("locking strength should be translated for mysql, got %v", result.Statement.SQL.String())
+		}
+	case "sqlite":
+		var result User
+		if err := DB.Clauses(clause.ForUpdate()).First(&result, user.ID).Error; err != nil {
+			t.Errorf("locking should be skipped for sqlite, got %v", err)
+		}
+
+		if err := DB.Clauses(clause.ForUpdate().SkipLocked()).Find(&[]User{}).Error; !errors.Is(err, gorm.ErrUnsupportedLocking) {
+			t.Errorf("should return ErrUnsupportedLocking for sqlite, got %v", err)
+		}
+	case "sqlserver":
+		if err := DB.Clauses(clause.ForUpdate()).Find(&[]User{}).Error; !errors.Is(err, gorm.ErrUnsupportedLocking) {
+			t.Errorf("should return ErrUnsupportedLocking for sqlserver, got %v", err)
+		}
+	}
+
+	db, err := gorm.Open(lockingDialector{Dialector: DB.Dialector}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open db, got %v", err)
+	}
+
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	var result1 User
+	if err := db.Clauses(clause.ForShare()).First(&result1, user.ID).Error; err != nil {
+		t.Errorf("no error should happen when locking is skipped, got %v", err)
+	}
+	CheckUser(t, result1, user)
+
+	result := db.Session(&gorm.Session{DryRun: true}).Clauses(clause.ForNoKeyUpdate().NoWait()).Find(&[]User{})
+	if !regexp.MustCompile(`FOR UPDATE NOWAIT$`).MatchString(result.Statement.SQL.String()) {
+		t.Errorf("locking should be translated, got %v", result.Statement.SQL.String())
+	}
+
+	if err := db.Clauses(clause.ForUpdate().SkipLocked()).Find(&[]User{}).Error; !errors.Is(err, gorm.ErrUnsupportedLocking) {
+		t.Errorf("should return ErrUnsupportedLocking, got %v", err)
+	}
+
+	if _, err := db.Model(&User{}).Clauses(clause.ForUpdate().SkipLocked()).Rows(); !errors.Is(err, gorm.ErrUnsupportedLocking) {
+		t.Errorf("should return ErrUnsupportedLocking for row query, got %v", err)
+	}
+}
+
 func TestScanNullValue(t *testing.T) {
 	user := GetUser("scan_null_value", Config{})
 	DB.Create(&user)
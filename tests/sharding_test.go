@@ -0,0 +1,172 @@
+package tests_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/plugin/sharding"
+)
+
+type ShardingOrder struct {
+	ID      int64
+	UserID  int64
+	Product string
+}
+
+type ShardingUser struct {
+	ID     int64
+	Name   string
+	Orders []ShardingOrder `gorm:"foreignKey:UserID"`
+}
+
+type ShardingProduct struct {
+	ID     int64
+	Name   string
+	Orders []ShardingOrder `gorm:"foreignKey:Product;references:Name"`
+}
+
+func TestSharding(t *testing.T) {
+	db, err := gorm.Open(DB.Dialector, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open db, got %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		table := fmt.Sprintf("sharding_orders_%d", i)
+		db.Migrator().DropTable(table)
+		if err := db.Table(table).AutoMigrate(&ShardingOrder{}); err != nil {
+			t.Fatalf("failed to migrate table %v, got %v", table, err)
+		}
+	}
+
+	if err := db.Use(sharding.Register(sharding.Config{
+		ShardingKey:         "user_id",
+		NumberOfShards:      4,
+		PrimaryKeyGenerator: (&sharding.Snowflake{Node: 1}).Generate,
+	}, &ShardingOrder{})); err != nil {
+		t.Fatalf("failed to use sharding plugin, got %v", err)
+	}
+
+	orders := []ShardingOrder{{UserID: 5, Product: "iPhone"}, {UserID: 5, Product: "iPad"}}
+	if err := db.Create(&orders).Error; err != nil {
+		t.Fatalf("failed to create orders, got %v", err)
+	}
+
+	order := ShardingOrder{UserID: 2, Product: "Mac"}
+	if err := db.Create(&order).Error; err != nil {
+		t.Fatalf("failed to create order, got %v", err)
+	}
+
+	if orders[0].ID == 0 || orders[1].ID == 0 || order.ID == 0 || orders[0].ID == orders[1].ID {
+		t.Errorf("primary keys should be generated, got %v, %v, %v", orders[0].ID, orders[1].ID, order.ID)
+	}
+
+	var count int64
+	DB.Table("sharding_orders_1").Where("user_id = ?", 5).Count(&count)
+	if count != 2 {
+		t.Errorf("orders should be created in shard sharding_orders_1, got %v", count)
+	}
+
+	var results []ShardingOrder
+	if err := db.Where("user_id = ?", 5).Order("id").Find(&results).Error; err != nil || len(results) != 2 {
+		t.Errorf("failed to query orders of shard, got %v, %v", err, len(results))
+	} else if results[0].ID != orders[0].ID || results[1].ID != orders[1].ID {
+		t.Errorf("invalid orders, got %+v", results)
+	}
+
+	var result ShardingOrder
+	if err := db.Where(map[string]interface{}{"user_id": 2}).First(&result).Error; err != nil || result.ID != order.ID {
+		t.Errorf("failed to query order with map conditions, got %v, %+v", err, result)
+	}
+
+	if err := db.Where("user_id IN ?", []int64{1, 5}).Find(&results).Error; len(results) != 2 || err != nil {
+		t.Errorf("failed to query orders with IN conditions of same shard, got %v, %v", err, len(results))
+	}
+
+	if err := db.Where("user_id IN ?", []int64{1, 2}).Find(&results).Error; !errors.Is(err, sharding.ErrCrossShards) {
+		t.Errorf("should return ErrCrossShards, got %v", err)
+	}
+
+	if err := db.Find(&results).Error; !errors.Is(err, sharding.ErrMissingShardingKey) {
+		t.Errorf("should return ErrMissingShardingKey, got %v", err)
+	}
+
+	if err := db.Where("user_id = ?", 5).Or("product = ?", "Mac").Find(&results).Error; !errors.Is(err, sharding.ErrMissingShardingKey) {
+		t.Errorf("should return ErrMissingShardingKey for OR conditions, got %v", err)
+	}
+
+	if err := db.Clauses(sharding.FanOut).Where("product LIKE ?", "i%").Order("id").Find(&results).Error; err != nil || len(results) != 2 {
+		t.Errorf("failed to query all shards, got %v, %v", err, len(results))
+	}
+
+	db.Clauses(sharding.FanOut).Model(&ShardingOrder{}).Count(&count)
+	if count != 3 {
+		t.Errorf("should count all shards, got %v", count)
+	}
+
+	db.Clauses(sharding.FanOut).Model(&ShardingOrder{}).Where("product = ?", "Mac").Count(&count)
+	if count != 1 {
+		t.Errorf("should count all shards with conditions, got %v", count)
+	}
+
+	stmt := db.Session(&gorm.Session{DryRun: true}).Clauses(sharding.FanOut).Where("product IN ?", []string{"iPad", "Mac"}).Find(&results).Statement
+	if !regexp.MustCompile(`SELECT \* FROM .sharding_orders_0. AS .sharding_orders. WHERE product IN \(.+,.+\) UNION ALL `).MatchString(stmt.SQL.String()) ||
+		!regexp.MustCompile(`\) AS .sharding_orders.$`).MatchString(stmt.SQL.String()) || len(stmt.Vars) != 8 {
+		t.Errorf("conditions should be pushed down to the shards, got %v, %v", stmt.SQL.String(), stmt.Vars)
+	}
+
+	var user ShardingUser
+	db.Migrator().DropTable(&ShardingUser{})
+	db.AutoMigrate(&ShardingUser{})
+	db.Create(&ShardingUser{ID: 5, Name: "sharding"})
+	if err := db.Preload("Orders").First(&user, 5).Error; err != nil || len(user.Orders) != 2 {
+		t.Errorf("failed to preload orders by the sharding key, got %v, %v", err, len(user.Orders))
+	}
+
+	var product ShardingProduct
+	db.Migrator().DropTable(&ShardingProduct{})
+	db.AutoMigrate(&ShardingProduct{})
+	db.Create(&ShardingProduct{Name: "Mac"})
+	if err := db.Preload("Orders").First(&product).Error; !errors.Is(err, sharding.ErrMissingShardingKey) {
+		t.Errorf("preloading by foreign key other than the sharding key should return ErrMissingShardingKey, got %v", err)
+	}
+
+	result = orders[0]
+	result.Product = "iPhone 12"
+	if err := db.Save(&result).Error; err != nil {
+		t.Errorf("failed to save order, got %v", err)
+	}
+
+	if err := db.Model(&ShardingOrder{}).Where("user_id = ?", 5).Where("id = ?", orders[1].ID).Update("product", "iPad Pro").Error; err != nil {
+		t.Errorf("failed to update order, got %v", err)
+	}
+
+	if err := db.Model(&ShardingOrder{}).Where("id = ?", orders[1].ID).Update("product", "iPad Pro").Error; !errors.Is(err, sharding.ErrMissingShardingKey) {
+		t.Errorf("should return ErrMissingShardingKey when updating, got %v", err)
+	}
+
+	db.Where("user_id = ?", 5).Order("id").Find(&results)
+	if len(results) != 2 || results[0].Product != "iPhone 12" || results[1].Product != "iPad Pro" {
+		t.Errorf("failed to update orders, got %+v", results)
+	}
+
+	if err := db.Delete(&order).Error; err != nil {
+		t.Errorf("failed to delete order, got %v", err)
+	}
+
+	if err := db.Where("user_id = ?", 2).First(&result).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("order should be deleted, got %v", err)
+	}
+
+	if err := db.Create(&ShardingOrder{Product: "Mac"}).Error; !errors.Is(err, sharding.ErrMissingShardingKey) {
+		t.Errorf("should return ErrMissingShardingKey when creating, got %v", err)
+	}
+
+	stmt = db.Session(&gorm.Session{DryRun: true}).Where("user_id = ?", 7).Find(&results).Statement
+	if !regexp.MustCompile(`FROM .sharding_orders_3. WHERE user_id = `).MatchString(stmt.SQL.String()) {
+		t.Errorf("table should be rewritten, got %v", stmt.SQL.String())
+	}
+}
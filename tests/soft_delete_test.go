@@ -5,6 +5,7 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"gorm.io/gorm"
 	. "gorm.io/gorm/utils/tests"
@@ -78,3 +79,118 @@ func TestDeletedAtUnMarshal(t *testing.T) {
 		t.Errorf("Failed, result.DeletedAt: %v is not same as expected.DeletedAt: %v", result.DeletedAt, expected.DeletedAt)
 	}
 }
+
+func TestRestore(t *testing.T) {
+	user := *GetUser("Restore", Config{})
+	DB.Save(&user)
+	DB.Delete(&user)
+
+	if err := DB.Restore(&user).Error; err != nil {
+		t.Fatalf("failed to restore, got %v", err)
+	}
+
+	if err := DB.First(&User{}, "name = ?", user.Name).Error; err != nil {
+		t.Errorf("should find restored record, got %v", err)
+	}
+
+	if err := DB.Restore(&User{}).Error; !errors.Is(err, gorm.ErrMissingWhereClause) {
+		t.Errorf("should return ErrMissingWhereClause when restoring without conditions, got %v", err)
+	}
+
+	if err := DB.Restore(&Company{}, "name = ?", "unknown").Error; !errors.Is(err, gorm.ErrInvalidField) {
+		t.Errorf("should return ErrInvalidField when restoring model without soft delete field, got %v", err)
+	}
+}
+
+type SoftDeleteUnixUser struct {
+	ID        uint
+	Name      string             `gorm:"uniqueIndex:idx_soft_delete_unix_name"`
+	DeletedAt gorm.DeletedAtUnix `gorm:"uniqueIndex:idx_soft_delete_unix_name"`
+}
+
+type SoftDeleteUnixMilliUser struct {
+	ID        uint
+	Name      string
+	DeletedAt gorm.DeletedAtUnixMilli
+}
+
+type SoftDeleteFlagUser struct {
+	ID        uint
+	Name      string
+	IsDeleted gorm.DeletedFlag `gorm:"deletedAtField:DeletedAt"`
+	DeletedAt int64
+}
+
+func TestSoftDeleteVariants(t *testing.T) {
+	DB.Migrator().DropTable(&SoftDeleteUnixUser{}, &SoftDeleteUnixMilliUser{}, &SoftDeleteFlagUser{})
+	if err := DB.AutoMigrate(&SoftDeleteUnixUser{}, &SoftDeleteUnixMilliUser{}, &SoftDeleteFlagUser{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	unixUser := SoftDeleteUnixUser{Name: "unix"}
+	DB.Create(&unixUser)
+	if err := DB.Delete(&unixUser).Error; err != nil {
+		t.Fatalf("failed to delete, got %v", err)
+	}
+
+	if err := DB.Create(&SoftDeleteUnixUser{Name: "unix"}).Error; err != nil {
+		t.Errorf("should create record with the name of soft deleted record, got %v", err)
+	}
+
+	var unixUsers []SoftDeleteUnixUser
+	if DB.Find(&unixUsers, "name = ?", "unix"); len(unixUsers) != 1 || unixUsers[0].ID == unixUser.ID {
+		t.Errorf("should not find soft deleted records, got %+v", unixUsers)
+	}
+
+	DB.Unscoped().First(&unixUser, unixUser.ID)
+	if now := time.Now().Unix(); unixUser.DeletedAt < gorm.DeletedAtUnix(now-60) || unixUser.DeletedAt > gorm.DeletedAtUnix(now) {
+		t.Errorf("deleted at should be unix seconds, got %v", unixUser.DeletedAt)
+	}
+
+	milliUser := SoftDeleteUnixMilliUser{Name: "milli"}
+	DB.Create(&milliUser)
+	DB.Delete(&milliUser)
+
+	if err := DB.First(&SoftDeleteUnixMilliUser{}, milliUser.ID).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("should not find soft deleted record, got %v", err)
+	}
+
+	DB.Unscoped().First(&milliUser, milliUser.ID)
+	if now := time.Now().UnixNano() / 1e6; milliUser.DeletedAt < gorm.DeletedAtUnixMilli(now-60000) || milliUser.DeletedAt > gorm.DeletedAtUnixMilli(now) {
+		t.Errorf("deleted at should be unix milliseconds, got %v", milliUser.DeletedAt)
+	}
+
+	if err := DB.Restore(&milliUser).Error; err != nil {
+		t.Errorf("failed to restore, got %v", err)
+	}
+
+	if err := DB.First(&milliUser, milliUser.ID).Error; err != nil || milliUser.DeletedAt != 0 {
+		t.Errorf("should find restored record, got %v, %v", err, milliUser.DeletedAt)
+	}
+
+	flagUser := SoftDeleteFlagUser{Name: "flag"}
+	DB.Create(&flagUser)
+
+	sql := DB.Session(&gorm.Session{DryRun: true}).Delete(&flagUser).Statement.SQL.String()
+	if !regexp.MustCompile(`SET .is_deleted.=.+,.deleted_at.=.+ WHERE .+ AND .soft_delete_flag_users.\..is_deleted. = `).MatchString(sql) {
+		t.Errorf("invalid sql generated, got %v", sql)
+	}
+
+	DB.Delete(&flagUser)
+	if err := DB.First(&SoftDeleteFlagUser{}, flagUser.ID).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("should not find soft deleted record, got %v", err)
+	}
+
+	DB.Unscoped().First(&flagUser, flagUser.ID)
+	if !flagUser.IsDeleted || flagUser.DeletedAt == 0 {
+		t.Errorf("should set the flag and deleted time, got %+v", flagUser)
+	}
+
+	if err := DB.Restore(&SoftDeleteFlagUser{}, "name = ?", "flag").Error; err != nil {
+		t.Errorf("failed to restore, got %v", err)
+	}
+
+	if err := DB.First(&flagUser, flagUser.ID).Error; err != nil || flagUser.IsDeleted || flagUser.DeletedAt != 0 {
+		t.Errorf("should find restored record, got %v, %+v", err, flagUser)
+	}
+}
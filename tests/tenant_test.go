@@ -0,0 +1,136 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TenantOrder struct {
+	ID        uint
+	TenantID  uint `gorm:"tenant"`
+	Name      string
+	DeletedAt gorm.DeletedAt
+}
+
+func TestTenant(t *testing.T) {
+	DB.Migrator().DropTable(&TenantOrder{})
+	if err := DB.AutoMigrate(&TenantOrder{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	tenant1 := DB.WithContext(gorm.WithTenant(context.Background(), 1))
+	tenant2 := DB.Session(&gorm.Session{Context: gorm.WithTenant(context.Background(), uint(2))})
+
+	orders := []TenantOrder{{Name: "order1"}, {Name: "order2", TenantID: 2}}
+	if err := tenant1.Create(&orders).Error; err != nil {
+		t.Fatalf("failed to create orders, got %v", err)
+	}
+
+	if orders[0].TenantID != 1 || orders[1].TenantID != 1 {
+		t.Errorf("tenant should be set when creating, got %+v", orders)
+	}
+
+	if err := tenant2.Model(&TenantOrder{}).Create(map[string]interface{}{"Name": "order3", "TenantID": 1}).Error; err != nil {
+		t.Fatalf("failed to create order with map, got %v", err)
+	}
+
+	if err := DB.Create(&TenantOrder{Name: "order4"}).Error; !errors.Is(err, gorm.ErrMissingTenant) {
+		t.Errorf("should return ErrMissingTenant when creating without tenant, got %v", err)
+	}
+
+	var results []TenantOrder
+	if err := tenant1.Find(&results).Error; err != nil || len(results) != 2 {
+		t.Errorf("should only find orders of tenant 1, got %v, %+v", err, results)
+	}
+
+	if err := tenant2.Where("name = ?", "order1").Or("name = ?", "order3").Find(&results).Error; err != nil || len(results) != 1 || results[0].Name != "order3" {
+		t.Errorf("OR conditions should be scoped by tenant, got %v, %+v", err, results)
+	}
+
+	if err := tenant2.Or("name = ?", "order1").Find(&results).Error; err != nil || len(results) != 0 {
+		t.Errorf("OR conditions should be scoped by tenant, got %v, %+v", err, results)
+	}
+
+	var result TenantOrder
+	if err := tenant2.First(&result, orders[0].ID).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("should not find order of other tenant, got %v", err)
+	}
+
+	var count int64
+	if err := tenant1.Model(&TenantOrder{}).Count(&count).Error; err != nil || count != 2 {
+		t.Errorf("should count orders of tenant 1, got %v, %v", err, count)
+	}
+
+	if err := DB.Find(&results).Error; !errors.Is(err, gorm.ErrMissingTenant) {
+		t.Errorf("should return ErrMissingTenant when querying without tenant, got %v", err)
+	}
+
+	if err := DB.Unscoped().Find(&results).Error; err != nil || len(results) != 3 {
+		t.Errorf("should find all orders with unscoped, got %v, %v", err, len(results))
+	}
+
+	if result := tenant2.Model(&TenantOrder{}).Where("name = ?", "order1").Update("name", "hacked"); result.Error != nil || result.RowsAffected != 0 {
+		t.Errorf("should not update orders of other tenant, got %v, %v", result.Error, result.RowsAffected)
+	}
+
+	if err := tenant2.Model(&TenantOrder{}).Update("name", "hacked").Error; !errors.Is(err, gorm.ErrMissingWhereClause) {
+		t.Errorf("should return ErrMissingWhereClause when updating without conditions, got %v", err)
+	}
+
+	order := orders[0]
+	order.Name = "order1-new"
+	if result := tenant1.Save(&order); result.Error != nil || result.RowsAffected != 1 {
+		t.Errorf("failed to save order, got %v, %v", result.Error, result.RowsAffected)
+	}
+
+	order.TenantID = 2
+	if err := tenant1.Save(&order).Error; err != nil {
+		t.Errorf("failed to save order, got %v", err)
+	}
+
+	if err := tenant1.Model(&order).Updates(map[string]interface{}{"tenant_id": 2}).Error; err != nil {
+		t.Errorf("failed to update order, got %v", err)
+	}
+
+	if err := tenant1.First(&result, orders[0].ID).Error; err != nil || result.TenantID != 1 {
+		t.Errorf("tenant of the order shouldn't be changed by updating, got %v, %+v", err, result)
+	}
+
+	if err := tenant2.Clauses(clause.OnConflict{UpdateAll: true}).Create(&TenantOrder{ID: orders[0].ID, Name: "hacked"}).Error; err != nil {
+		t.Errorf("failed to upsert order, got %v", err)
+	}
+
+	if err := tenant1.First(&result, orders[0].ID).Error; err != nil || result.TenantID != 1 || result.Name != "order1-new" {
+		t.Errorf("should not upsert orders of other tenant, got %v, %+v", err, result)
+	}
+
+	upserted := TenantOrder{ID: orders[0].ID, Name: "order1-upserted"}
+	if err := tenant1.Clauses(clause.OnConflict{UpdateAll: true}).Create(&upserted).Error; err != nil {
+		t.Errorf("failed to upsert order, got %v", err)
+	}
+
+	if err := tenant1.First(&result, orders[0].ID).Error; err != nil || result.Name != "order1-upserted" {
+		t.Errorf("failed to upsert order of the tenant, got %v, %+v", err, result)
+	}
+
+	if result := tenant2.Delete(&TenantOrder{}, orders[0].ID); result.Error != nil || result.RowsAffected != 0 {
+		t.Errorf("should not delete orders of other tenant, got %v, %v", result.Error, result.RowsAffected)
+	}
+
+	if err := tenant2.Delete(&TenantOrder{}).Error; !errors.Is(err, gorm.ErrMissingWhereClause) {
+		t.Errorf("should return ErrMissingWhereClause when deleting without conditions, got %v", err)
+	}
+
+	if result := tenant1.Unscoped().Delete(&TenantOrder{}, orders[1].ID); result.Error != nil || result.RowsAffected != 1 {
+		t.Errorf("failed to delete order, got %v, %v", result.Error, result.RowsAffected)
+	}
+
+	tenant1.Find(&results)
+	if len(results) != 1 || results[0].Name != "order1-upserted" {
+		t.Errorf("invalid orders of tenant 1, got %+v", results)
+	}
+}
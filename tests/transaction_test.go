@@ -3,7 +3,9 @@ package tests_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"gorm.io/gorm"
 	. "gorm.io/gorm/utils/tests"
@@ -283,6 +285,191 @@ func TestNestedTransactionWithBlock(t *testing.T) {
 	}
 }
 
+type savePointDialector struct {
+	gorm.Dialector
+	savePoints []string
+	released   []string
+	rollbacked []string
+}
+
+func (d *savePointDialector) SavePoint(tx *gorm.DB, name string) error {
+	d.savePoints = append(d.savePoints, name)
+	return d.Dialector.(gorm.SavePointerDialectorInterface).SavePoint(tx, name)
+}
+
+func (d *savePointDialector) RollbackTo(tx *gorm.DB, name string) error {
+	d.rollbacked = append(d.rollbacked, name)
+	return d.Dialector.(gorm.SavePointerDialectorInterface).RollbackTo(tx, name)
+}
+
+func (d *savePointDialector) ReleaseSavePoint(tx *gorm.DB, name string) error {
+	d.released = append(d.released, name)
+	return tx.Exec("RELEASE SAVEPOINT " + name).Error
+}
+
+func TestNestedTransactionSavePoints(t *testing.T) {
+	if DB.Dialector.Name() == "sqlserver" {
+		t.Skip("sqlserver doesn't support release save point")
+	}
+
+	dialector := &savePointDialector{Dialector: DB.Dialector}
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open db, got %v", err)
+	}
+
+	var (
+		user  = *GetUser("transaction-savepoint", Config{})
+		user1 = *GetUser("transaction-savepoint-1", Config{})
+		user2 = *GetUser("transaction-savepoint-2", Config{})
+		user3 = *GetUser("transaction-savepoint-3", Config{})
+	)
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		tx.Create(&user)
+
+		return tx.Transaction(func(tx1 *gorm.DB) error {
+			tx1.Create(&user1)
+
+			if err := tx1.Transaction(func(tx2 *gorm.DB) error {
+				tx2.Create(&user2)
+				return errors.New("rollback")
+			}); err == nil {
+				t.Fatalf("nested transaction should returns error")
+			}
+
+			func() {
+				defer func() { recover() }()
+				tx1.Transaction(func(tx2 *gorm.DB) error {
+					tx2.Create(&user3)
+					panic("rollback")
+				})
+			}()
+
+			return nil
+		})
+	}); err != nil {
+		t.Fatalf("no error should return, but got %v", err)
+	}
+
+	if err := DB.First(&User{}, "name = ?", user1.Name).Error; err != nil {
+		t.Errorf("Should find saved record")
+	}
+
+	if err := DB.First(&User{}, "name = ?", user2.Name).Error; err == nil {
+		t.Errorf("Should not find rollbacked record")
+	}
+
+	if err := DB.First(&User{}, "name = ?", user3.Name).Error; err == nil {
+		t.Errorf("Should not find rollbacked record when panic")
+	}
+
+	if fmt.Sprint(dialector.savePoints) != "[sp1 sp2 sp2]" {
+		t.Errorf("save points should be named by depth, got %v", dialector.savePoints)
+	}
+
+	if fmt.Sprint(dialector.rollbacked) != "[sp2 sp2]" {
+		t.Errorf("should rollback to save points, got %v", dialector.rollbacked)
+	}
+
+	if fmt.Sprint(dialector.released) != "[sp1]" {
+		t.Errorf("should release save points, got %v", dialector.released)
+	}
+}
+
+func TestNestedTransactionReleaseSavePoint(t *testing.T) {
+	if DB.Dialector.Name() == "sqlserver" {
+		t.Skip("sqlserver doesn't support release save point")
+	}
+
+	errDiscard := errors.New("discard")
+	if err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Transaction(func(tx1 *gorm.DB) error {
+			return tx1.Create(GetUser("transaction-release-savepoint", Config{})).Error
+		}); err != nil {
+			t.Fatalf("nested transaction should succeed, got %v", err)
+		}
+
+		// the save point is released by default after the nested transaction succeeded
+		if err := tx.Session(&gorm.Session{}).Exec("RELEASE SAVEPOINT sp1").Error; err == nil {
+			t.Errorf("should failed to release the released save point")
+		}
+		return errDiscard
+	}); !errors.Is(err, errDiscard) {
+		t.Errorf("should returns the error of the transaction, got %v", err)
+	}
+}
+
+func TestTransactionWithRetryPolicy(t *testing.T) {
+	var (
+		errRetryable = errors.New("serialization failure")
+		attempts     int
+		policy       = &gorm.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     time.Millisecond,
+			Jitter:      0.5,
+			Retryable:   func(err error) bool { return errors.Is(err, errRetryable) },
+		}
+		db = DB.Session(&gorm.Session{RetryPolicy: policy})
+	)
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		attempts++
+		user := *GetUser(fmt.Sprintf("transaction-retry-%v", attempts), Config{})
+		tx.Create(&user)
+		if attempts < 3 {
+			return errRetryable
+		}
+		return nil
+	}); err != nil || attempts != 3 {
+		t.Fatalf("transaction should succeed after retries, got %v, attempts %v", err, attempts)
+	}
+
+	if err := DB.First(&User{}, "name = ?", "transaction-retry-1").Error; err == nil {
+		t.Errorf("Should not find record of failed attempt")
+	}
+
+	if err := DB.First(&User{}, "name = ?", "transaction-retry-3").Error; err != nil {
+		t.Errorf("Should find saved record, got %v", err)
+	}
+
+	attempts = 0
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		attempts++
+		return errRetryable
+	}); !errors.Is(err, errRetryable) || attempts != 3 {
+		t.Errorf("should stop after max attempts, got %v, attempts %v", err, attempts)
+	}
+
+	attempts = 0
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		attempts++
+		return errors.New("not retryable")
+	}); err == nil || attempts != 1 {
+		t.Errorf("should not retry on not retryable errors, got %v, attempts %v", err, attempts)
+	}
+
+	attempts = 0
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return tx.Transaction(func(tx2 *gorm.DB) error {
+			attempts++
+			return errRetryable
+		})
+	}); !errors.Is(err, errRetryable) || attempts != 3 {
+		t.Errorf("only the outermost transaction should retry, got %v, attempts %v", err, attempts)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts = 0
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		attempts++
+		cancel()
+		return errRetryable
+	}); !errors.Is(err, context.Canceled) || !errors.Is(err, errRetryable) || attempts != 1 {
+		t.Errorf("should stop retrying when context canceled, got %v, attempts %v", err, attempts)
+	}
+}
+
 func TestTransactionOnClosedConn(t *testing.T) {
 	DB, err := OpenTestConnection()
 	if err != nil {
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/utils"
 	. "gorm.io/gorm/utils/tests"
 )
@@ -588,6 +589,43 @@ func TestUpdateFromSubQuery(t *testing.T) {
 	}
 }
 
+func TestUpdateWithReturning(t *testing.T) {
+	users := []User{*GetUser("update_returning_1", Config{}), *GetUser("update_returning_2", Config{})}
+	DB.Create(&users)
+
+	if DB.Dialector.Name() != "postgres" {
+		if err := DB.Clauses(clause.Returning{}).Model(&users[0]).Update("age", 20).Error; !errors.Is(err, gorm.ErrUnsupportedReturning) {
+			t.Errorf("should returns ErrUnsupportedReturning, got %v", err)
+		}
+		t.Skip("RETURNING is not supported by current dialect")
+	}
+
+	result := DB.Session(&gorm.Session{DryRun: true}).Clauses(clause.Returning{}).Model(&users[0]).Update("age", 20)
+	if !regexp.MustCompile(`^UPDATE .users. SET .+ WHERE .+ RETURNING \*$`).MatchString(result.Statement.SQL.String()) {
+		t.Errorf("invalid update SQL with returning, got %v", result.Statement.SQL.String())
+	}
+
+	var results []User
+	if err := DB.Clauses(clause.Returning{Columns: []clause.Column{{Name: "name"}, {Name: "age"}}}).Model(&results).
+		Where("name LIKE ?", "update_returning%").Update("age", gorm.Expr("age + ?", 100)).Error; err != nil {
+		t.Fatalf("failed to update with returning, got error %v", err)
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	if len(results) != 2 || results[0].Name != "update_returning_1" || results[0].Age != users[0].Age+100 || results[1].Age != users[1].Age+100 {
+		t.Errorf("returned rows should be scanned into destination, got %+v", results)
+	}
+
+	var user User
+	if err := DB.Clauses(clause.Returning{}).Model(&user).Where("id = ?", users[0].ID).Updates(map[string]interface{}{"age": 1}).Error; err != nil {
+		t.Fatalf("failed to update with returning, got error %v", err)
+	}
+
+	if user.ID != users[0].ID || user.Name != users[0].Name || user.Age != 1 {
+		t.Errorf("returned row should be scanned into model, got %+v", user)
+	}
+}
+
 func TestSave(t *testing.T) {
 	user := *GetUser("save", Config{})
 	DB.Create(&user)
@@ -652,3 +690,85 @@ func TestSaveWithPrimaryValue(t *testing.T) {
 		t.Errorf("failed to find created record, got error: %v, result: %+v", err, result4)
 	}
 }
+
+type VersionedUser struct {
+	ID      uint
+	Name    string
+	Version gorm.Version
+}
+
+func TestUpdateWithVersion(t *testing.T) {
+	DB.Migrator().DropTable(&VersionedUser{})
+	if err := DB.AutoMigrate(&VersionedUser{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	user := VersionedUser{Name: "versioned"}
+	DB.Create(&user)
+
+	var user1, user2 VersionedUser
+	DB.First(&user1, user.ID)
+	DB.First(&user2, user.ID)
+
+	user1.Name = "versioned-1"
+	if err := DB.Save(&user1).Error; err != nil || user1.Version != 1 {
+		t.Fatalf("failed to save, got %v, version %v", err, user1.Version)
+	}
+
+	user2.Name = "versioned-2"
+	if err := DB.Save(&user2).Error; !errors.Is(err, gorm.ErrStaleObject) || user2.Version != 0 {
+		t.Errorf("should return ErrStaleObject when saving stale object, got %v, version %v", err, user2.Version)
+	}
+
+	if err := DB.Model(&user2).Update("name", "versioned-2").Error; !errors.Is(err, gorm.ErrStaleObject) {
+		t.Errorf("should return ErrStaleObject when updating stale object, got %v", err)
+	}
+
+	if err := DB.Model(&user1).Updates(map[string]interface{}{"name": "versioned-3"}).Error; err != nil || user1.Version != 2 {
+		t.Errorf("failed to update, got %v, version %v", err, user1.Version)
+	}
+
+	var result VersionedUser
+	if DB.First(&result, user.ID); result.Name != "versioned-3" || result.Version != 2 {
+		t.Errorf("invalid record, got %+v", result)
+	}
+
+	if err := DB.Model(&VersionedUser{}).Where("id = ?", user.ID).Update("name", "versioned-4").Error; err != nil {
+		t.Errorf("should not lock records not loaded, got %v", err)
+	}
+
+	if result := DB.Model(&VersionedUser{}).Where("id = ?", 0).Update("name", "versioned-4"); result.Error != nil || result.RowsAffected != 0 {
+		t.Errorf("should not return ErrStaleObject without version condition, got %v, %v", result.Error, result.RowsAffected)
+	}
+
+	omits := append(make([]string, 0, 2), "id")
+	if err := DB.Omit(omits...).Model(&user1).Update("name", "versioned-4").Error; err != nil || user1.Version != 3 {
+		t.Errorf("failed to update, got %v, version %v", err, user1.Version)
+	} else if omits[:2][1] != "" {
+		t.Errorf("omitted columns shouldn't be changed, got %v", omits[:2])
+	}
+
+	if err := DB.Model(&user2).Update("version", 10).Error; err != nil {
+		t.Errorf("failed to update version explicitly, got %v", err)
+	}
+
+	if DB.First(&result, user.ID); result.Name != "versioned-4" || result.Version != 10 {
+		t.Errorf("invalid record, got %+v", result)
+	}
+
+	if DB.Dialector.Name() == "postgres" {
+		result.Name = "versioned-5"
+		if err := DB.Clauses(clause.Returning{Columns: []clause.Column{{Name: "name"}}}).Save(&result).Error; err != nil || result.Version != 11 {
+			t.Errorf("failed to save with returning, got %v, version %v", err, result.Version)
+		}
+
+		if err := DB.Clauses(clause.Returning{}).Model(&result).Update("name", "versioned-6").Error; err != nil || result.Version != 12 {
+			t.Errorf("failed to update with returning, got %v, version %v", err, result.Version)
+		}
+	}
+
+	stmt := DB.Session(&gorm.Session{DryRun: true}).Save(&result).Statement
+	if !regexp.MustCompile(`SET .name.=.+,.version.=.version. \+ 1 WHERE .versioned_users.\..version. = .+ AND .id. = `).MatchString(stmt.SQL.String()) {
+		t.Errorf("version should be increased with the version condition, got %v", stmt.SQL.String())
+	}
+}
@@ -1,9 +1,13 @@
 package tests_test
 
 import (
+	"errors"
+	"regexp"
 	"testing"
 	"time"
 
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
 	"gorm.io/gorm/clause"
 	. "gorm.io/gorm/utils/tests"
 )
@@ -152,6 +156,47 @@ func TestUpsertWithSave(t *testing.T) {
 	// }
 }
 
+func TestUpsertWithUpdateAll(t *testing.T) {
+	lang := Language{Code: "upsert-update-all", Name: "Upsert-update-all"}
+	if err := DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(&lang).Error; err != nil {
+		t.Fatalf("failed to upsert, got %v", err)
+	}
+
+	lang.Name = "Upsert-update-all-new"
+	if err := DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(&lang).Error; err != nil {
+		t.Fatalf("failed to upsert, got %v", err)
+	}
+
+	var result Language
+	if err := DB.First(&result, "code = ?", lang.Code).Error; err != nil {
+		t.Errorf("Failed to query lang, got error %v", err)
+	} else {
+		AssertEqual(t, result, lang)
+	}
+}
+
+func TestUpsertWithMerge(t *testing.T) {
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{})
+	db.Callback().Create().Replace("gorm:create", callbacks.Create(&callbacks.Config{UpsertWithMerge: true}))
+
+	langs := []Language{{Code: "upsert-merge-1", Name: "Upsert-merge-1"}, {Code: "upsert-merge-2", Name: "Upsert-merge-2"}}
+	result := db.Session(&gorm.Session{DryRun: true}).Clauses(clause.OnConflict{UpdateAll: true}).Create(&langs)
+	if !regexp.MustCompile(`^MERGE INTO .languages. USING \(VALUES \(.+\),\(.+\)\) AS .excluded. \(.code.,.name.\) ON .languages.\..code. = .excluded.\..code. WHEN MATCHED THEN UPDATE SET .name.=.excluded.\..name. WHEN NOT MATCHED THEN INSERT \(.code.,.name.\) VALUES \(.excluded.\..code.,.excluded.\..name.\);$`).MatchString(result.Statement.SQL.String()) {
+		t.Errorf("invalid merge SQL, got %v", result.Statement.SQL.String())
+	}
+
+	result = db.Session(&gorm.Session{DryRun: true}).Create(&langs)
+	if !regexp.MustCompile(`^INSERT INTO .languages.`).MatchString(result.Statement.SQL.String()) {
+		t.Errorf("creating without on conflict should use INSERT, got %v", result.Statement.SQL.String())
+	}
+
+	// the auto increment primary key isn't included in the values
+	user := *GetUser("upsert-merge", Config{})
+	if err := db.Session(&gorm.Session{DryRun: true}).Clauses(clause.OnConflict{DoNothing: true}).Create(&user).Error; !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("should returns ErrInvalidData when conflict columns aren't included in the values, got %v", err)
+	}
+}
+
 func TestFindOrInitialize(t *testing.T) {
 	var user1, user2, user3, user4, user5, user6 User
 	if err := DB.Where(&User{Name: "find or init", Age: 33}).FirstOrInit(&user1).Error; err != nil {
@@ -0,0 +1,103 @@
+package gorm
+
+import (
+	"reflect"
+	"sync"
+)
+
+// transactionHooksKey the cache key of the hooks of a transaction, the transaction is identified by its conn pool
+type transactionHooksKey struct {
+	ConnPool ConnPool
+}
+
+// transactionHooks hooks queued on a transaction, fired after the outermost transaction committed or rollbacked
+type transactionHooks struct {
+	mux        sync.Mutex
+	onCommit   []func()
+	onRollback []func()
+}
+
+// OnCommit registers fc to be called after the transaction committed, calls it immediately if not in a transaction
+func (db *DB) OnCommit(fc func()) *DB {
+	if hooks := db.transactionHooks(); hooks != nil {
+		hooks.mux.Lock()
+		hooks.onCommit = append(hooks.onCommit, fc)
+		hooks.mux.Unlock()
+	} else {
+		fc()
+	}
+	return db
+}
+
+// OnRollback registers fc to be called after the transaction rollbacked, does nothing if not in a transaction
+func (db *DB) OnRollback(fc func()) *DB {
+	if hooks := db.transactionHooks(); hooks != nil {
+		hooks.mux.Lock()
+		hooks.onRollback = append(hooks.onRollback, fc)
+		hooks.mux.Unlock()
+	}
+	return db
+}
+
+func (db *DB) transactionHooks() *transactionHooks {
+	if db.cacheStore != nil && isComparable(db.Statement.ConnPool) {
+		if v, ok := db.cacheStore.Load(transactionHooksKey{db.Statement.ConnPool}); ok {
+			return v.(*transactionHooks)
+		}
+	}
+	return nil
+}
+
+func (db *DB) setupTransactionHooks() {
+	if db.cacheStore != nil && isComparable(db.Statement.ConnPool) {
+		db.cacheStore.Store(transactionHooksKey{db.Statement.ConnPool}, &transactionHooks{})
+	}
+}
+
+// fireTransactionHooks fires the commit or rollback hooks of the transaction, and removes all its hooks
+func (db *DB) fireTransactionHooks(committed bool) {
+	if hooks := db.transactionHooks(); hooks != nil {
+		db.cacheStore.Delete(transactionHooksKey{db.Statement.ConnPool})
+
+		hooks.mux.Lock()
+		fcs := hooks.onRollback
+		if committed {
+			fcs = hooks.onCommit
+		}
+		hooks.onCommit, hooks.onRollback = nil, nil
+		hooks.mux.Unlock()
+
+		for _, fc := range fcs {
+			fc()
+		}
+	}
+}
+
+// savePoint returns the numbers of the queued hooks, used to roll back the hooks to a save point
+func (hooks *transactionHooks) savePoint() (commits, rollbacks int) {
+	hooks.mux.Lock()
+	defer hooks.mux.Unlock()
+	return len(hooks.onCommit), len(hooks.onRollback)
+}
+
+// rollbackTo discards the commit hooks queued after the save point and fires the rollback hooks queued after it
+func (hooks *transactionHooks) rollbackTo(commits, rollbacks int) {
+	hooks.mux.Lock()
+	var fcs []func()
+	if commits < len(hooks.onCommit) {
+		hooks.onCommit = hooks.onCommit[:commits]
+	}
+	if rollbacks < len(hooks.onRollback) {
+		fcs = hooks.onRollback[rollbacks:]
+		hooks.onRollback = hooks.onRollback[:rollbacks:rollbacks]
+	}
+	hooks.mux.Unlock()
+
+	for _, fc := range fcs {
+		fc()
+	}
+}
+
+func isComparable(value interface{}) bool {
+	return value != nil && reflect.TypeOf(value).Comparable()
+}
@@ -0,0 +1,111 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+const (
+	// DefaultRetryMaxAttempts default max attempts of RetryPolicy
+	DefaultRetryMaxAttempts = 3
+	// DefaultRetryMaxBackoff default max wait duration between retries of RetryPolicy
+	DefaultRetryMaxBackoff = 5 * time.Second
+)
+
+// RetryPolicy retry policy of transactions, the whole transaction block will be re-run on a new transaction
+//    db.Session(&gorm.Session{RetryPolicy: &gorm.RetryPolicy{MaxAttempts: 5, Backoff: 10 * time.Millisecond}}).Transaction(func(tx *gorm.DB) error {
+//      // ...
+//    }, &sql.TxOptions{Isolation: sql.LevelSerializable})
+type RetryPolicy struct {
+	// MaxAttempts max attempts including the first one, defaults to DefaultRetryMaxAttempts
+	MaxAttempts int
+	// Backoff wait duration before the first retry, doubled for each retry
+	Backoff time.Duration
+	// MaxBackoff max wait duration between retries, defaults to DefaultRetryMaxBackoff
+	MaxBackoff time.Duration
+	// Jitter randomly reduces the wait duration by up to the fraction, 0 ~ 1
+	Jitter float64
+	// Retryable reports whether the error could be retried, defaults to the dialector's RetryableErrorDialectorInterface
+	Retryable func(error) bool
+}
+
+func (policy *RetryPolicy) shouldRetry(db *DB, err error, attempt int) bool {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = DefaultRetryMaxAttempts
+	}
+
+	if attempt >= maxAttempts {
+		return false
+	}
+
+	if policy.Retryable != nil {
+		return policy.Retryable(err)
+	} else if classifier, ok := db.Dialector.(RetryableErrorDialectorInterface); ok {
+		return classifier.IsRetryableError(err)
+	}
+	return false
+}
+
+// backoff returns the wait duration before the next attempt
+func (policy *RetryPolicy) backoff(attempt int) time.Duration {
+	maxBackoff := policy.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRetryMaxBackoff
+	}
+
+	backoff := policy.Backoff
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	if policy.Jitter > 0 {
+		backoff -= time.Duration(float64(backoff) * policy.Jitter * rand.Float64())
+	}
+	return backoff
+}
+
+// wait waits before the next attempt, returns the context error that wraps the last error if it is done
+func (policy *RetryPolicy) wait(ctx context.Context, attempt int, lastErr error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	timer := time.NewTimer(policy.backoff(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return retryCanceledError{err: lastErr, ctxErr: ctx.Err()}
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryCanceledError the context is done before retrying, matches both the last error and the context error with errors.Is
+type retryCanceledError struct {
+	err    error
+	ctxErr error
+}
+
+func (e retryCanceledError) Error() string {
+	return e.err.Error() + "; " + e.ctxErr.Error()
+}
+
+func (e retryCanceledError) Is(target error) bool {
+	return errors.Is(e.err, target)
+}
+
+func (e retryCanceledError) As(target interface{}) bool {
+	return errors.As(e.err, target)
+}
+
+func (e retryCanceledError) Unwrap() error {
+	return e.ctxErr
+}
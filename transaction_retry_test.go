@@ -0,0 +1,25 @@
+package gorm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	results := []struct {
+		Policy  RetryPolicy
+		Attempt int
+		Backoff time.Duration
+	}{
+		{RetryPolicy{Backoff: 10 * time.Millisecond}, 1, 10 * time.Millisecond},
+		{RetryPolicy{Backoff: 10 * time.Millisecond}, 3, 40 * time.Millisecond},
+		{RetryPolicy{Backoff: 10 * time.Millisecond, MaxBackoff: 30 * time.Millisecond}, 3, 30 * time.Millisecond},
+		{RetryPolicy{Backoff: time.Second}, 100, DefaultRetryMaxBackoff},
+	}
+
+	for _, result := range results {
+		if backoff := result.Policy.backoff(result.Attempt); backoff != result.Backoff {
+			t.Errorf("backoff of attempt %v should be %v, got %v", result.Attempt, result.Backoff, backoff)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package gorm
+
+import (
+	"reflect"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Version optimistic lock version, updating a record with Version increases its version,
+// returns ErrStaleObject if the record has been updated by others since it was loaded
+//    type User struct {
+//      ID      uint
+//      Name    string
+//      Version gorm.Version
+//    }
+//
+//    db.Save(&user) // UPDATE users SET name='jinzhu',version=version+1 WHERE id = 1 AND version = 1
+type Version int64
+
+func (Version) UpdateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{VersionUpdateClause{Field: f}}
+}
+
+type VersionUpdateClause struct {
+	Field *schema.Field
+}
+
+func (vc VersionUpdateClause) Name() string {
+	return ""
+}
+
+func (vc VersionUpdateClause) Build(clause.Builder) {
+}
+
+func (vc VersionUpdateClause) MergeClause(*clause.Clause) {
+}
+
+func (vc VersionUpdateClause) ModifyStatement(stmt *Statement) {
+	if _, ok := stmt.Clauses["version_enabled"]; ok || stmt.SQL.Len() > 0 || stmt.ReflectValue.Kind() != reflect.Struct {
+		return
+	}
+
+	// only lock the loaded record
+	for _, field := range stmt.Schema.PrimaryFields {
+		if _, isZero := field.ValueOf(stmt.ReflectValue); isZero {
+			return
+		}
+	}
+
+	// updating the version explicitly
+	if values, ok := stmt.Dest.(map[string]interface{}); ok {
+		if _, ok := values[vc.Field.Name]; ok {
+			return
+		} else if _, ok := values[vc.Field.DBName]; ok {
+			return
+		}
+	}
+
+	version, _ := vc.Field.ValueOf(stmt.ReflectValue)
+	stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: vc.Field.DBName}, Value: version},
+	}})
+	stmt.Omits = append(append(make([]string, 0, len(stmt.Omits)+1), stmt.Omits...), vc.Field.DBName)
+	stmt.Clauses["version_enabled"] = clause.Clause{Expression: vc}
+}
+
+// Assignment returns the assignment increasing the version
+func (vc VersionUpdateClause) Assignment(stmt *Statement) clause.Assignment {
+	return clause.Assignment{
+		Column: clause.Column{Name: vc.Field.DBName},
+		Value:  clause.Expr{SQL: stmt.Quote(clause.Column{Name: vc.Field.DBName}) + " + 1"},
+	}
+}
+
+// Increase increases the version of the updated value unless it has been refreshed by RETURNING,
+// returns ErrStaleObject if nothing updated, does nothing if the version condition isn't applied
+func (vc VersionUpdateClause) Increase(stmt *Statement) {
+	c, ok := stmt.Clauses["WHERE"]
+	if !ok {
+		return
+	}
+
+	where, _ := c.Expression.(clause.Where)
+	if version, ok := vc.condition(where.Exprs); !ok {
+		return
+	} else if stmt.DB.RowsAffected == 0 {
+		stmt.AddError(ErrStaleObject)
+	} else if current, _ := vc.Field.ValueOf(stmt.ReflectValue); current == version {
+		stmt.AddError(vc.Field.Set(stmt.ReflectValue, reflect.ValueOf(version).Int()+1))
+	}
+}
+
+// condition returns the version of the version condition in the expressions
+func (vc VersionUpdateClause) condition(exprs []clause.Expression) (version interface{}, ok bool) {
+	for _, expr := range exprs {
+		switch v := expr.(type) {
+		case clause.Eq:
+			if v.Column == (clause.Column{Table: clause.CurrentTable, Name: vc.Field.DBName}) {
+				return v.Value, true
+			}
+		case clause.AndConditions:
+			if version, ok = vc.condition(v.Exprs); ok {
+				return
+			}
+		}
+	}
+	return nil, false
+}